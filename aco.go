@@ -0,0 +1,179 @@
+package dijkstrapf
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ACOConfig tunes FindPathACO. Zero fields take the defaults noted.
+type ACOConfig struct {
+	// Ants walk from the start in every iteration (default 20).
+	Ants int
+	// Iterations bounds the number of iterations (default 50).
+	Iterations int
+	// Alpha weights the pheromone in the ants' choices (default 1).
+	Alpha float64
+	// Beta weights the step cost and the heuristic guidance towards the
+	// goal (default 2).
+	Beta float64
+	// Evaporation is the fraction of pheromone lost per iteration
+	// (default 0.1).
+	Evaporation float64
+	// Seed seeds the ants' random choices.
+	Seed int64
+	// Rand, if set, supplies the random choices instead of Seed.
+	Rand *rand.Rand
+	// Iteration, if set, is called after every iteration with the best
+	// path so far, empty until an ant reaches the goal, and the pheromone
+	// level of every cell, indexed by y*width+x. Returning false stops the
+	// search.
+	Iteration func(i int, best Path, pheromone []float64) bool
+}
+
+func (c ACOConfig) withDefaults() ACOConfig {
+	if c.Ants <= 0 {
+		c.Ants = 20
+	}
+	if c.Iterations <= 0 {
+		c.Iterations = 50
+	}
+	if c.Alpha == 0 {
+		c.Alpha = 1
+	}
+	if c.Beta == 0 {
+		c.Beta = 2
+	}
+	if c.Evaporation <= 0 || c.Evaporation >= 1 {
+		c.Evaporation = 0.1
+	}
+	return c
+}
+
+// FindPathACO searches with ant colony optimisation. In every iteration a
+// colony of ants walks from the start, each choosing among the cells it
+// has not visited yet with a probability that grows with the pheromone on
+// them and shrinks with the step cost and with how far the step leads away
+// from the goal. Ants that reach the goal leave pheromone on their cells in inverse
+// proportion to their path cost, so later ants favour good routes.
+//
+// The result is usually close to, but not guaranteed to be, the shortest
+// path, and ErrNoPath only means that no ant found the goal. It is meant
+// for experimenting with metaheuristics next to the exact solvers. The
+// same seed and grid always give the same result.
+func (g *Graph) FindPathACO(cfg ACOConfig, opts ...Option) (Path, error) {
+	cfg = cfg.withDefaults()
+	return g.run("aco", func(g *Graph, o *Options) (Path, error) {
+		return aco(g, o, cfg)
+	}, opts)
+}
+
+func aco(g *Graph, o *Options, cfg ACOConfig) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+	h := g.heuristic(o)
+	r := cfg.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(cfg.Seed))
+	}
+	n := g.nodeCount()
+	g.resetSearch()
+
+	pheromone := make([]float64, n)
+	for i := range pheromone {
+		pheromone[i] = 1
+	}
+	// visited[x] == stamp marks the cells the current ant has been on.
+	visited := make([]int, n)
+	stamp := 0
+	best := Path{Cost: math.Inf(1)}
+	maxSteps := 4 * n
+
+	var weights []float64
+	var choices []edge
+	for it := 0; it < cfg.Iterations; it++ {
+		var found [][]int
+		var costs []float64
+		for ant := 0; ant < cfg.Ants; ant++ {
+			stamp++
+			walk := []int{src}
+			visited[src] = stamp
+			cost := 0.0
+			for cur := src; cur != dst && len(walk) < maxSteps; {
+				g.settle(o, cur, cost)
+				hcur := h(g.point(cur), g.goal)
+				weights, choices = weights[:0], choices[:0]
+				total := 0.0
+				for _, e := range adj[cur] {
+					if visited[e.to] == stamp {
+						continue
+					}
+					c, err := g.stepCost(o, cur, e)
+					if err != nil {
+						return Path{}, err
+					}
+					if math.IsInf(c, 1) {
+						continue
+					}
+					// Steps towards the goal add no excess over the
+					// heuristic and are the most attractive.
+					excess := max(0, c+h(g.point(e.to), g.goal)-hcur)
+					eta := 1 / max(c+excess, 1e-9)
+					w := math.Pow(pheromone[e.to], cfg.Alpha) * math.Pow(eta, cfg.Beta)
+					weights = append(weights, w)
+					choices = append(choices, edge{e.to, c})
+					total += w
+				}
+				if len(choices) == 0 {
+					break
+				}
+				pick := len(choices) - 1
+				for i, x := 0, r.Float64()*total; i < len(weights); i++ {
+					if x -= weights[i]; x < 0 {
+						pick = i
+						break
+					}
+				}
+				cur = choices[pick].to
+				cost += choices[pick].cost
+				visited[cur] = stamp
+				walk = append(walk, cur)
+			}
+			if walk[len(walk)-1] != dst {
+				continue
+			}
+			found = append(found, walk)
+			costs = append(costs, cost)
+			if cost < best.Cost {
+				best = Path{Points: make([]Point, len(walk)), Cost: cost}
+				for i, id := range walk {
+					best.Points[i] = g.point(id)
+				}
+			}
+		}
+
+		for i := range pheromone {
+			pheromone[i] *= 1 - cfg.Evaporation
+		}
+		for k, walk := range found {
+			deposit := 1 / max(costs[k], 1e-9)
+			for _, id := range walk {
+				pheromone[id] += deposit
+			}
+		}
+		if cfg.Iteration != nil && !cfg.Iteration(it, best, pheromone) {
+			break
+		}
+	}
+
+	g.finish(o, dst, best.Cost)
+	if best.Points == nil {
+		return Path{}, ErrNoPath
+	}
+	return best, nil
+}
@@ -0,0 +1,80 @@
+package dijkstrapf
+
+import "math"
+
+// HeuristicViolation is a place where a heuristic breaks a guarantee: its
+// Estimate of the cost from From to the goal exceeds Bound.
+type HeuristicViolation struct {
+	From, To Point
+	// Estimate is h(From, goal).
+	Estimate float64
+	// Bound is the true cost from From to the goal for an overestimate,
+	// and the cost of stepping to To plus h(To, goal) for an
+	// inconsistency.
+	Bound float64
+}
+
+// HeuristicReport is the result of CheckHeuristic.
+type HeuristicReport struct {
+	// Overestimates lists the cells from which h overestimates the cost of
+	// reaching the goal. To is the goal.
+	Overestimates []HeuristicViolation
+	// Inconsistent lists the steps From→To over which h drops by more
+	// than the step costs.
+	Inconsistent []HeuristicViolation
+}
+
+// Admissible reports whether h never overestimated.
+func (r HeuristicReport) Admissible() bool { return len(r.Overestimates) == 0 }
+
+// Consistent reports whether h obeyed the triangle inequality on every
+// step. Consistent heuristics are also admissible.
+func (r HeuristicReport) Consistent() bool { return len(r.Inconsistent) == 0 }
+
+// heuristicSlack absorbs rounding errors in the comparisons.
+const heuristicSlack = 1e-9
+
+// CheckHeuristic tests h against the true costs of reaching the goal from
+// every cell of g, priced as a solve with opts would price them. A* finds
+// optimal paths with an admissible heuristic, and never has to reopen a
+// cell with a consistent one. The check runs a full reverse Dijkstra
+// search, which replaces the graph's latest search result. Cells that
+// cannot reach the goal are skipped.
+func (g *Graph) CheckHeuristic(h HeuristicFunc, opts ...Option) (HeuristicReport, error) {
+	if !g.hasGoal {
+		return HeuristicReport{}, ErrNoGoal
+	}
+	o := buildOptions(append(opts[:len(opts):len(opts)], WithReverse()))
+	goal := g.goal
+	dist, _, err := g.search(o, g.id(goal), nil)
+	if err != nil {
+		return HeuristicReport{}, err
+	}
+
+	var r HeuristicReport
+	adj := g.adjacency()
+	for id, d := range dist {
+		if math.IsInf(d, 1) {
+			continue
+		}
+		p := g.point(id)
+		est := h(p, goal)
+		if est > d+heuristicSlack*max(1, d) {
+			r.Overestimates = append(r.Overestimates, HeuristicViolation{p, goal, est, d})
+		}
+		for _, e := range adj[id] {
+			if math.IsInf(dist[e.to], 1) {
+				continue
+			}
+			c, err := g.stepCost(o, id, e)
+			if err != nil {
+				return HeuristicReport{}, err
+			}
+			q := g.point(e.to)
+			if bound := c + h(q, goal); est > bound+heuristicSlack*max(1, bound) {
+				r.Inconsistent = append(r.Inconsistent, HeuristicViolation{p, q, est, bound})
+			}
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,61 @@
+package dijkstrapf
+
+import "fmt"
+
+// Alternatives returns up to n distinct routes from the start to the goal,
+// the shortest one first. After each route is found, the steps it used
+// cost an extra penalty times their own cost in later searches, in both
+// directions, so later routes avoid the earlier ones where a reasonable
+// detour exists. Higher penalties give more distinct but longer routes.
+// The returned costs are the true, unpenalised costs. Fewer than n routes
+// are returned when no further distinct route turns up.
+func (g *Graph) Alternatives(n int, penalty float64, opts ...Option) ([]Path, error) {
+	if penalty < 0 {
+		return nil, ErrNegativeCost
+	}
+	o := buildOptions(opts)
+	adj := g.adjacency()
+	used := make(map[[2]int]int)
+	key := func(a, b int) [2]int {
+		if a > b {
+			a, b = b, a
+		}
+		return [2]int{a, b}
+	}
+	var costErr error
+	po := &Options{Queue: o.Queue, Cost: func(from, to Point) float64 {
+		a, b := g.id(from), g.id(to)
+		e, _ := findEdge(adj[a], b)
+		c, err := g.stepCost(o, a, e)
+		if err != nil {
+			costErr = err
+			return 0
+		}
+		return c * (1 + penalty*float64(used[key(a, b)]))
+	}}
+
+	var out []Path
+	seen := make(map[string]bool)
+	for tries := 0; len(out) < n && tries < 2*n; tries++ {
+		p, err := dijkstra(g, po)
+		if costErr != nil {
+			return out, costErr
+		}
+		if err != nil {
+			return out, err
+		}
+		cost := 0.0
+		for i := 1; i < len(p.Points); i++ {
+			a, b := g.id(p.Points[i-1]), g.id(p.Points[i])
+			e, _ := findEdge(adj[a], b)
+			c, _ := g.stepCost(o, a, e)
+			cost += c
+			used[key(a, b)]++
+		}
+		if sig := fmt.Sprint(p.Points); !seen[sig] {
+			seen[sig] = true
+			out = append(out, Path{Points: p.Points, Cost: cost})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,180 @@
+package dijkstrapf
+
+import (
+	"math"
+	"time"
+)
+
+// FindPathAnytime runs ARA*, an anytime variant of weighted A*. It first
+// finds a path with the heuristic inflated by the epsilon set with
+// WithEpsilon (3 if none is set), then repeatedly lowers epsilon and
+// improves that path, reusing the earlier search effort, until the path is
+// optimal or the deadline passes. A zero deadline means no deadline. The
+// first path is always completed, whatever the deadline.
+//
+// Every time a better path is found, improved, if not nil, is called with
+// it and with the current bound: the path costs at most bound times the
+// optimum. FindPathAnytime returns the last path and its bound.
+func (g *Graph) FindPathAnytime(deadline time.Time, improved func(p Path, bound float64), opts ...Option) (Path, float64, error) {
+	bound := math.Inf(1)
+	path, err := g.run("anytime", func(g *Graph, o *Options) (Path, error) {
+		if o.Reverse {
+			return Path{}, errForwardOnly
+		}
+		a, err := newARA(g, o)
+		if err != nil {
+			return Path{}, err
+		}
+		var best Path
+		for eps := a.eps; ; {
+			if err := a.improvePath(deadline, best.Points == nil); err != nil {
+				return Path{}, err
+			}
+			if math.IsInf(a.dist[a.dst], 1) {
+				return Path{}, ErrNoPath
+			}
+			if a.expired {
+				break
+			}
+			bound = min(eps, a.bound())
+			best = Path{Points: g.reconstruct(a.prev, a.dst), Cost: a.dist[a.dst]}
+			if improved != nil {
+				improved(best, bound)
+			}
+			if bound <= 1 {
+				break
+			}
+			eps = max(1, min(eps-0.5, bound))
+			a.restart(eps)
+		}
+		g.finish(o, a.dst, best.Cost)
+		return best, nil
+	}, opts)
+	return path, bound, err
+}
+
+// ara holds the state of an ARA* search between its iterations.
+type ara struct {
+	g        *Graph
+	o        *Options
+	h        HeuristicFunc
+	eps      float64
+	src, dst int
+	adj      [][]edge
+	dist     []float64
+	prev     []int
+	open     PriorityQueue
+	inOpen   []bool
+	closed   []bool
+	incons   []int
+	expired  bool
+}
+
+func newARA(g *Graph, o *Options) (*ara, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return nil, err
+	}
+	n := g.nodeCount()
+	dist, prev := g.resetSearch()
+	a := &ara{
+		g: g, o: o, h: g.heuristic(o), eps: 3,
+		src: src, dst: dst, adj: g.adjacency(),
+		dist: dist, prev: prev,
+		open: o.newQueue(n), inOpen: make([]bool, n), closed: make([]bool, n),
+	}
+	if o.Epsilon > 1 {
+		a.eps = o.Epsilon
+	}
+	dist[src] = 0
+	a.open.Push(src, a.key(src))
+	a.inOpen[src] = true
+	return a, nil
+}
+
+func (a *ara) key(n int) float64 {
+	return a.dist[n] + a.eps*a.h(a.g.point(n), a.g.goal)
+}
+
+// improvePath expands nodes until the goal's cost is no more than the
+// smallest key left open. Unless first is set it gives up once the
+// deadline passes, setting a.expired.
+func (a *ara) improvePath(deadline time.Time, first bool) error {
+	g, o := a.g, a.o
+	for expansions := 0; a.open.Len() > 0; expansions++ {
+		if !first && !deadline.IsZero() && expansions%256 == 0 && time.Now().After(deadline) {
+			a.expired = true
+			return nil
+		}
+		cur, k := a.open.PopMin()
+		if k >= a.dist[a.dst] {
+			// Put it back for the next iteration.
+			a.open.Push(cur, k)
+			return nil
+		}
+		a.inOpen[cur] = false
+		a.closed[cur] = true
+		g.settle(o, cur, a.dist[cur])
+		for _, e := range a.adj[cur] {
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return err
+			}
+			nd := a.dist[cur] + c
+			if nd >= a.dist[e.to] {
+				continue
+			}
+			g.relax(o, e.to, a.dist[e.to], nd)
+			a.dist[e.to] = nd
+			a.prev[e.to] = cur
+			switch {
+			case a.closed[e.to]:
+				a.incons = append(a.incons, e.to)
+			case a.inOpen[e.to]:
+				a.open.DecreaseKey(e.to, a.key(e.to))
+			default:
+				a.open.Push(e.to, a.key(e.to))
+				a.inOpen[e.to] = true
+			}
+		}
+	}
+	return nil
+}
+
+// bound returns how far the goal's cost can be from the optimum: the
+// goal's cost divided by the smallest uninflated f-value still to examine.
+func (a *ara) bound() float64 {
+	least := a.dist[a.dst]
+	f := func(n int) {
+		least = min(least, a.dist[n]+a.h(a.g.point(n), a.g.goal))
+	}
+	for n, ok := range a.inOpen {
+		if ok {
+			f(n)
+		}
+	}
+	for _, n := range a.incons {
+		f(n)
+	}
+	if least <= 0 {
+		return 1
+	}
+	return a.dist[a.dst] / least
+}
+
+// restart moves the inconsistent nodes back to the open list, re-keys it
+// for eps and forgets which nodes were closed.
+func (a *ara) restart(eps float64) {
+	a.eps = eps
+	for _, n := range a.incons {
+		a.inOpen[n] = true
+	}
+	a.incons = a.incons[:0]
+	a.open = a.o.newQueue(len(a.dist))
+	for n, ok := range a.inOpen {
+		if ok {
+			a.open.Push(n, a.key(n))
+		}
+	}
+	clear(a.closed)
+}
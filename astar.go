@@ -0,0 +1,143 @@
+package dijkstrapf
+
+import "math"
+
+// HeuristicFunc estimates the remaining cost from a cell to the goal. A*
+// returns optimal paths only if it never overestimates.
+type HeuristicFunc func(from, goal Point) float64
+
+// WithHeuristic sets the heuristic used by A* and the other informed
+// solvers.
+func WithHeuristic(h HeuristicFunc) Option {
+	return func(o *Options) { o.Heuristic = h }
+}
+
+// WithEpsilon makes A* weight its heuristic by eps. With an admissible
+// heuristic the path found costs at most eps times the optimum, and larger
+// values usually expand far fewer cells. eps below 1 is treated as 1.
+func WithEpsilon(eps float64) Option {
+	return func(o *Options) { o.Epsilon = eps }
+}
+
+// epsilon returns the heuristic weight to use, at least 1.
+func (o *Options) epsilon() float64 {
+	return max(1, o.Epsilon)
+}
+
+// heuristic returns o.Heuristic, or a safe default: the grid distance
+// scaled by the cheapest cell weight. With a custom cost function nothing
+// is known about step costs, and an added edge may be a shortcut, so in
+// those cases the default degrades to zero.
+func (g *Graph) heuristic(o *Options) HeuristicFunc {
+	if o.Heuristic != nil {
+		return o.Heuristic
+	}
+	if o.Cost != nil {
+		return func(Point, Point) float64 { return 0 }
+	}
+	w := g.minWeight()
+	if g.hasShortcut(w) {
+		return func(Point, Point) float64 { return 0 }
+	}
+	return func(a, b Point) float64 {
+		dx, dy := g.delta(a, b)
+		return w * g.gridDistance(dx, dy)
+	}
+}
+
+func (g *Graph) minWeight() float64 {
+	least := math.Inf(1)
+	for y, row := range g.weights {
+		for x, w := range row {
+			if w < least && g.gridMatrix[y][x] != Wall {
+				least = w
+			}
+		}
+	}
+	if math.IsInf(least, 1) {
+		return 0
+	}
+	return least
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// FindPathAStar runs A* from the start to the goal cell.
+func (g *Graph) FindPathAStar(opts ...Option) (Path, error) {
+	return g.run("astar", astar, opts)
+}
+
+func astar(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+	h := g.heuristic(o)
+	eps := o.epsilon()
+	goal := g.goal
+
+	n := g.nodeCount()
+	dist, prev := g.resetSearch()
+	queued := make([]bool, n)
+	dist[src] = 0
+	pq := g.frontier(o, n, prev)
+	pq.Push(src, eps*h(g.start, goal))
+	queued[src] = true
+	for pq.Len() > 0 {
+		cur, _ := pq.PopMin()
+		queued[cur] = false
+		g.settle(o, cur, dist[cur])
+		if cur == dst {
+			break
+		}
+		if err := g.cancelled(o); err != nil {
+			return g.partial(o, dist, prev, err)
+		}
+		for _, e := range adj[cur] {
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return Path{}, err
+			}
+			nd := dist[cur] + c
+			if nd == dist[e.to] && queued[e.to] && g.preferParent(o, prev, e.to, cur) {
+				prev[e.to] = cur
+				pq.DecreaseKey(e.to, nd+eps*h(g.point(e.to), goal))
+				continue
+			}
+			if nd >= dist[e.to] {
+				continue
+			}
+			g.relax(o, e.to, dist[e.to], nd)
+			dist[e.to] = nd
+			prev[e.to] = cur
+			f := nd + eps*h(g.point(e.to), goal)
+			if queued[e.to] {
+				pq.DecreaseKey(e.to, f)
+			} else {
+				// Also reopens closed nodes when the heuristic is
+				// inconsistent.
+				pq.Push(e.to, f)
+				queued[e.to] = true
+			}
+		}
+	}
+
+	g.finish(o, dst, dist[dst])
+	if math.IsInf(dist[dst], 1) {
+		return g.partial(o, dist, prev, ErrNoPath)
+	}
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, nil
+}
+
+func init() {
+	Register("astar", astar)
+}
@@ -0,0 +1,123 @@
+package dijkstrapf
+
+import (
+	"math"
+	"slices"
+)
+
+// SetAvoidance turns local collision avoidance between the agents of s on
+// or off. It is off by default, and agents then walk through each other.
+//
+// With avoidance on, no two agents share a cell. Agents move in the order
+// they were spawned, which is also their priority: an agent whose next
+// cell is taken waits a tick; if the agent in its way has arrived and will
+// not move again, it plans a way around it instead; and if the two want
+// to swap cells, the one of lower priority steps aside to a free
+// neighbouring cell and plans again from there. This is no substitute for
+// planning the agents together: two agents meeting head-on in a corridor
+// one cell wide, with no room to step aside, block each other for good.
+func (s *Sim) SetAvoidance(on bool) { s.avoid = on }
+
+// occupancy maps every cell with an agent on it to that agent.
+func (s *Sim) occupancy() map[Point]*Agent {
+	occupied := make(map[Point]*Agent, len(s.agents))
+	for _, a := range s.agents {
+		occupied[a.pos] = a
+	}
+	return occupied
+}
+
+// advance moves a one cell along its route unless another agent is in the
+// way, and keeps occupied up to date.
+func (s *Sim) advance(a *Agent, occupied map[Point]*Agent) {
+	b := occupied[a.route[0]]
+	switch {
+	case b == nil:
+	case b.Arrived():
+		if s.plan(a, s.parked()) != nil || occupied[a.route[0]] != nil {
+			a.waited++
+			return
+		}
+		a.replans++
+	case len(b.route) > 0 && b.route[0] == a.pos && b.id < a.id:
+		s.sidestep(a, b, occupied)
+		return
+	default:
+		a.waited++
+		return
+	}
+	s.moveTo(a, a.route[0], occupied)
+	a.route = a.route[1:]
+}
+
+// sidestep moves a out of the way of b, which wants a's cell, and plans
+// a's route again from its new cell. It prefers cells off b's route.
+func (s *Sim) sidestep(a, b *Agent, occupied map[Point]*Agent) {
+	to := -1
+	for _, e := range s.g.adjacency()[s.g.id(a.pos)] {
+		q := s.g.point(e.to)
+		if occupied[q] != nil {
+			continue
+		}
+		if !slices.Contains(b.route, q) {
+			to = e.to
+			break
+		}
+		if to < 0 {
+			to = e.to
+		}
+	}
+	if to < 0 {
+		a.waited++
+		return
+	}
+	s.moveTo(a, s.g.point(to), occupied)
+	if s.plan(a, nil) == nil {
+		a.replans++
+	}
+}
+
+func (s *Sim) moveTo(a *Agent, p Point, occupied map[Point]*Agent) {
+	delete(occupied, a.pos)
+	a.pos = p
+	occupied[p] = a
+}
+
+// parked returns the cells of the agents that have arrived.
+func (s *Sim) parked() map[Point]bool {
+	cells := make(map[Point]bool)
+	for _, a := range s.agents {
+		if a.Arrived() {
+			cells[a.pos] = true
+		}
+	}
+	return cells
+}
+
+// detour returns the plan options of s with entering the cells in avoid
+// made impossible.
+func (s *Sim) detour(avoid map[Point]bool) []Option {
+	base := buildOptions(s.opts).Cost
+	cost := func(from, to Point) float64 {
+		switch {
+		case avoid[to]:
+			return math.Inf(1)
+		case base != nil:
+			return base(from, to)
+		}
+		return s.g.edgeCost(from, to)
+	}
+	return append(s.opts[:len(s.opts):len(s.opts)], WithCost(cost))
+}
+
+// edgeCost returns the cost of the cheapest edge from a to b, or +Inf if
+// there is none.
+func (g *Graph) edgeCost(a, b Point) float64 {
+	c, to := math.Inf(1), g.id(b)
+	for _, e := range g.adjacency()[g.id(a)] {
+		if e.to == to {
+			c = min(c, e.cost)
+		}
+	}
+	return c
+}
@@ -0,0 +1,90 @@
+package dijkstrapf
+
+import "math"
+
+// Query asks for the shortest path between two cells.
+type Query struct {
+	Start, Goal Point
+}
+
+// Result answers one Query.
+type Result struct {
+	Path Path
+	Err  error
+}
+
+// SolveMany answers every query with Dijkstra's algorithm. Queries sharing
+// a start are answered from a single search that runs until all of their
+// goals are settled, so a matrix of n starts and m goals costs n searches
+// rather than n*m. The graph's own start and goal are left untouched; the
+// results are returned in query order.
+func (g *Graph) SolveMany(queries []Query, opts ...Option) []Result {
+	o := buildOptions(opts)
+	parent, span := rootSpan(o).start("dijkstrapf.solve_many")
+	defer span.End()
+	g.setGridAttributes(span)
+	span.SetAttribute("dijkstrapf.algorithm", "dijkstra")
+	span.SetAttribute("dijkstrapf.queries", len(queries))
+	results := make([]Result, len(queries))
+
+	bySource := make(map[Point][]int)
+	var order []Point
+	for i, q := range queries {
+		if err := g.checkEndpoint(q.Start); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := g.checkEndpoint(q.Goal); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if _, seen := bySource[q.Start]; !seen {
+			order = append(order, q.Start)
+		}
+		bySource[q.Start] = append(bySource[q.Start], i)
+	}
+
+	expanded := 0
+	for _, src := range order {
+		idx := bySource[src]
+		pending := make(map[int]bool)
+		for _, i := range idx {
+			pending[g.id(queries[i].Goal)] = true
+		}
+		var search Span
+		g.tracing, search = parent.start("dijkstrapf.search")
+		dist, prev, err := g.search(o, g.id(src), func(node int) bool {
+			delete(pending, node)
+			return len(pending) == 0
+		})
+		search.SetAttribute("dijkstrapf.expanded", g.stats.Expanded)
+		expanded += g.stats.Expanded
+		for _, i := range idx {
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			dst := g.id(queries[i].Goal)
+			if math.IsInf(dist[dst], 1) {
+				results[i].Err = ErrNoPath
+				continue
+			}
+			results[i].Path = Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}
+		}
+		g.tracing = spanParent{}
+		search.End()
+	}
+	span.SetAttribute("dijkstrapf.expanded", expanded)
+	return results
+}
+
+// checkEndpoint reports why p cannot start or end a path, if it cannot.
+func (g *Graph) checkEndpoint(p Point) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	if g.IsWall(p) {
+		return ErrNoPath
+	}
+	return nil
+}
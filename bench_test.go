@@ -0,0 +1,31 @@
+package dijkstrapf_test
+
+import (
+	"fmt"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+// BenchmarkSolve runs every registered algorithm on every standard map
+// family and size, e.g. BenchmarkSolve/maze/128/astar.
+func BenchmarkSolve(b *testing.B) {
+	for _, family := range dijkstrapf.MapFamilies() {
+		for _, size := range dijkstrapf.BenchSizes {
+			g, err := dijkstrapf.GenerateFamily(family, size, 1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, algo := range dijkstrapf.Algorithms() {
+				b.Run(fmt.Sprintf("%s/%d/%s", family, size, algo), func(b *testing.B) {
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						if _, err := g.Solve(algo); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
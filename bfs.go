@@ -0,0 +1,62 @@
+package dijkstrapf
+
+import "math"
+
+// FindPathBFS runs a breadth-first search, which finds the path with the
+// fewest steps and ignores cell weights when choosing it. The returned cost
+// is still the weighted cost of walking that path.
+func (g *Graph) FindPathBFS(opts ...Option) (Path, error) {
+	return g.run("bfs", bfs, opts)
+}
+
+func bfs(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+
+	dist, prev := g.resetSearch()
+	dist[src] = 0
+	queue := []int{src}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		g.settle(o, cur, dist[cur])
+		if cur == dst {
+			break
+		}
+		if err := g.cancelled(o); err != nil {
+			return g.partial(o, dist, prev, err)
+		}
+		for _, e := range adj[cur] {
+			if !math.IsInf(dist[e.to], 1) {
+				continue
+			}
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return Path{}, err
+			}
+			if math.IsInf(c, 1) {
+				continue
+			}
+			g.relax(o, e.to, dist[e.to], dist[cur]+c)
+			dist[e.to] = dist[cur] + c
+			prev[e.to] = cur
+			queue = append(queue, e.to)
+		}
+	}
+
+	g.finish(o, dst, dist[dst])
+	if math.IsInf(dist[dst], 1) {
+		return g.partial(o, dist, prev, ErrNoPath)
+	}
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, nil
+}
+
+func init() {
+	Register("bfs", bfs)
+}
@@ -0,0 +1,376 @@
+package dijkstrapf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// BinaryVersion is the version of the binary format WriteBinary writes.
+const BinaryVersion = 1
+
+// binaryMagic starts every file in the binary format.
+var binaryMagic = []byte("DPFG")
+
+// Section tags of the binary format.
+const (
+	sectionCells   = "CELL"
+	sectionWeights = "WGHT"
+	sectionTerrain = "TERR"
+	sectionLayout  = "LAYT"
+	sectionEdges   = "EDGE"
+)
+
+// migrations[v] rewrites the sections of a version v file into those of
+// version v+1. When the format changes, bump BinaryVersion and add the
+// step from the previous version here, so that files written by older
+// releases keep loading.
+var migrations = map[uint16]func(sections map[string][]byte) error{}
+
+// WriteBinary writes g in the binary format read by LoadBinary. Unlike
+// WriteGrid it keeps everything about the grid: arbitrary weights, the
+// terrain table, the diagonal, corner and wrap settings and the edges
+// added or removed with AddEdge and RemoveEdge.
+//
+// The format is the magic bytes "DPFG", the version as a big-endian
+// uint16 and a sequence of sections, each a four-byte tag, the length of
+// its payload as a big-endian uint32 and the payload. Integers in the
+// payloads are uvarints and floats are big-endian IEEE 754 bits.
+func (g *Graph) WriteBinary(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	buf.Write(binary.BigEndian.AppendUint16(nil, BinaryVersion))
+
+	// CELL: width, height and the marker of every cell, row by row.
+	b := binary.AppendUvarint(nil, uint64(g.width))
+	b = binary.AppendUvarint(b, uint64(g.height))
+	for _, row := range g.gridMatrix {
+		for _, c := range row {
+			b = append(b, byte(c))
+		}
+	}
+	writeSection(&buf, sectionCells, b)
+
+	// WGHT: the weight of every cell.
+	b = b[:0]
+	for _, row := range g.weights {
+		for _, w := range row {
+			b = appendFloat(b, w)
+		}
+	}
+	writeSection(&buf, sectionWeights, b)
+
+	// TERR: the terrain table, then 1 + the terrain index of every cell,
+	// or 0.
+	b = binary.AppendUvarint(b[:0], uint64(len(g.terrains)))
+	for _, t := range g.terrains {
+		b = appendString(b, t.Name)
+		b = appendFloat(b, t.Cost)
+		b = append(b, t.Symbol)
+		b = appendString(b, t.Color)
+	}
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			b = append(b, byte(g.terrainOf(Point{x, y})+1))
+		}
+	}
+	writeSection(&buf, sectionTerrain, b)
+
+	// LAYT: diagonal moves, wrapping, the corner rule and the diagonal
+	// cost.
+	b = append(b[:0], boolByte(g.diagonal), boolByte(g.wrap), byte(g.corners))
+	b = appendFloat(b, g.DiagonalCost())
+	writeSection(&buf, sectionLayout, b)
+
+	// EDGE: the added edges as source, target and cost, then the removed
+	// grid moves as source and target, all by node id.
+	froms := make([]int, 0, len(g.extra))
+	for from := range g.extra {
+		froms = append(froms, from)
+	}
+	sort.Ints(froms)
+	n := 0
+	for _, from := range froms {
+		n += len(g.extra[from])
+	}
+	b = binary.AppendUvarint(b[:0], uint64(n))
+	for _, from := range froms {
+		for _, e := range g.extra[from] {
+			b = binary.AppendUvarint(b, uint64(from))
+			b = binary.AppendUvarint(b, uint64(e.to))
+			b = appendFloat(b, e.cost)
+		}
+	}
+	removed := make([][2]int, 0, len(g.removed))
+	for m := range g.removed {
+		removed = append(removed, m)
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i][0] != removed[j][0] {
+			return removed[i][0] < removed[j][0]
+		}
+		return removed[i][1] < removed[j][1]
+	})
+	b = binary.AppendUvarint(b, uint64(len(removed)))
+	for _, m := range removed {
+		b = binary.AppendUvarint(b, uint64(m[0]))
+		b = binary.AppendUvarint(b, uint64(m[1]))
+	}
+	writeSection(&buf, sectionEdges, b)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeSection(buf *bytes.Buffer, tag string, payload []byte) {
+	buf.WriteString(tag)
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(payload))))
+	buf.Write(payload)
+}
+
+func appendFloat(b []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// IsBinary reports whether data starts like a file in the binary format.
+func IsBinary(data []byte) bool {
+	return bytes.HasPrefix(data, binaryMagic)
+}
+
+// LoadBinary reads a graph written by WriteBinary, by this release or an
+// older one. Sections it does not know are skipped. It returns an error
+// wrapping ErrBadMap for malformed input and for files written in a newer
+// version of the format.
+func LoadBinary(r io.Reader) (*Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !IsBinary(data) || len(data) < len(binaryMagic)+2 {
+		return nil, fmt.Errorf("%w: not a binary map", ErrBadMap)
+	}
+	version := binary.BigEndian.Uint16(data[len(binaryMagic):])
+	if version == 0 || version > BinaryVersion {
+		return nil, fmt.Errorf("%w: unsupported binary map version %d", ErrBadMap, version)
+	}
+
+	sections := make(map[string][]byte)
+	for rest := data[len(binaryMagic)+2:]; len(rest) > 0; {
+		if len(rest) < 8 {
+			return nil, fmt.Errorf("%w: truncated section header", ErrBadMap)
+		}
+		tag, n := string(rest[:4]), binary.BigEndian.Uint32(rest[4:8])
+		rest = rest[8:]
+		if uint64(n) > uint64(len(rest)) {
+			return nil, fmt.Errorf("%w: section %s is truncated", ErrBadMap, tag)
+		}
+		sections[tag] = rest[:n]
+		rest = rest[n:]
+	}
+	for v := version; v < BinaryVersion; v++ {
+		if err := migrations[v](sections); err != nil {
+			return nil, fmt.Errorf("%w: upgrading from version %d: %w", ErrBadMap, v, err)
+		}
+	}
+	g, err := decodeSections(sections)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadMap, err)
+	}
+	return g, nil
+}
+
+// decodeSections builds the graph from the sections of a current version
+// file. Only the cells are required.
+func decodeSections(sections map[string][]byte) (*Graph, error) {
+	cells, ok := sections[sectionCells]
+	if !ok {
+		return nil, fmt.Errorf("no %s section", sectionCells)
+	}
+	d := &decoder{data: cells}
+	width, height := d.int(), d.int()
+	if d.err != nil || width == 0 || height == 0 || width*height != len(d.data) {
+		return nil, fmt.Errorf("bad %s section", sectionCells)
+	}
+	g := NewGraph(width, height)
+	markers := d.data
+	size := width * height
+
+	if data, ok := sections[sectionWeights]; ok {
+		d := &decoder{data: data}
+		for id := 0; id < size; id++ {
+			if err := g.SetWeight(g.point(id), d.float()); err != nil || d.err != nil {
+				return nil, fmt.Errorf("bad %s section", sectionWeights)
+			}
+		}
+	}
+
+	if data, ok := sections[sectionTerrain]; ok {
+		d := &decoder{data: data}
+		n := d.int()
+		g.terrains = nil
+		for i := 0; i < n && d.err == nil; i++ {
+			t := Terrain{Name: d.string(), Cost: d.float(), Symbol: d.byte(), Color: d.string()}
+			if d.err == nil {
+				if err := g.DefineTerrain(t); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for id := 0; id < size && d.err == nil; id++ {
+			i := int(d.byte())
+			switch {
+			case d.err != nil, i == 0:
+			case i > len(g.terrains):
+				return nil, fmt.Errorf("bad terrain index %d", i)
+			default:
+				g.SetTerrain(g.point(id), g.terrains[i-1].Name)
+			}
+		}
+		if d.err != nil {
+			return nil, fmt.Errorf("bad %s section", sectionTerrain)
+		}
+	}
+
+	for id, c := range markers {
+		p := g.point(id)
+		switch c {
+		case Empty:
+		case Wall:
+			g.SetWall(p, true)
+		case Start:
+			if g.hasStart {
+				return nil, fmt.Errorf("second start at %v", p)
+			}
+			g.SetStart(p)
+		case Goal:
+			if g.hasGoal {
+				return nil, fmt.Errorf("second goal at %v", p)
+			}
+			g.SetGoal(p)
+		default:
+			return nil, fmt.Errorf("unknown cell marker %d at %v", c, p)
+		}
+	}
+
+	if data, ok := sections[sectionLayout]; ok {
+		d := &decoder{data: data}
+		diagonal, wrap, corners, ratio := d.byte(), d.byte(), CornerRule(d.byte()), d.float()
+		if d.err != nil || corners > CornerNever {
+			return nil, fmt.Errorf("bad %s section", sectionLayout)
+		}
+		g.SetDiagonal(diagonal != 0)
+		g.SetWrap(wrap != 0)
+		g.SetCornerRule(corners)
+		if err := g.SetDiagonalCost(ratio); err != nil {
+			return nil, err
+		}
+	}
+
+	if data, ok := sections[sectionEdges]; ok {
+		d := &decoder{data: data}
+		node := func() int {
+			id := d.int()
+			if id >= size {
+				d.err = fmt.Errorf("node %d out of range", id)
+			}
+			return id
+		}
+		for n := d.int(); n > 0 && d.err == nil; n-- {
+			from, to, cost := node(), node(), d.float()
+			if d.err == nil {
+				if err := g.AddEdge(g.point(from), g.point(to), cost); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for n := d.int(); n > 0 && d.err == nil; n-- {
+			from, to := node(), node()
+			if d.err == nil {
+				if g.removed == nil {
+					g.removed = make(map[[2]int]bool)
+				}
+				g.removed[[2]int{from, to}] = true
+			}
+		}
+		if d.err != nil {
+			return nil, fmt.Errorf("bad %s section: %w", sectionEdges, d.err)
+		}
+	}
+	g.stale = true
+	return g, nil
+}
+
+// decoder reads the values of a section payload. The first error sticks
+// and makes every later read return the zero value.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+var errShortSection = errors.New("section ends early")
+
+func (d *decoder) int() int {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 || v > math.MaxInt32 {
+		d.err = errShortSection
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(v)
+}
+
+func (d *decoder) byte() byte {
+	if d.err == nil && len(d.data) < 1 {
+		d.err = errShortSection
+	}
+	if d.err != nil {
+		return 0
+	}
+	c := d.data[0]
+	d.data = d.data[1:]
+	return c
+}
+
+func (d *decoder) float() float64 {
+	if d.err == nil && len(d.data) < 8 {
+		d.err = errShortSection
+	}
+	if d.err != nil {
+		return 0
+	}
+	f := math.Float64frombits(binary.BigEndian.Uint64(d.data))
+	d.data = d.data[8:]
+	return f
+}
+
+func (d *decoder) string() string {
+	n := d.int()
+	if d.err == nil && n > len(d.data) {
+		d.err = errShortSection
+	}
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
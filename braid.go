@@ -0,0 +1,72 @@
+package dijkstrapf
+
+import "math/rand"
+
+// Braid turns a perfect maze into a braided one, with loops and more than
+// one way between its cells, by removing the fraction p of its dead ends:
+// a dead end is opened up by knocking out one of the walls around it that
+// separates it from another passage, preferring a wall that also fixes a
+// dead end on the far side. Dead ends are picked at random with seed. It
+// returns the number of walls knocked out. Braid suits mazes whose
+// corridors are one cell wide and separated by walls one cell thick, such
+// as those of GenerateMaze.
+func (g *Graph) Braid(p float64, seed int64) int {
+	return g.BraidFrom(p, rand.New(rand.NewSource(seed)))
+}
+
+// BraidFrom is Braid drawing from r.
+func (g *Graph) BraidFrom(p float64, r *rand.Rand) int {
+	var ends []Point
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			if c := (Point{x, y}); g.deadEnd(c) {
+				ends = append(ends, c)
+			}
+		}
+	}
+	r.Shuffle(len(ends), func(i, j int) { ends[i], ends[j] = ends[j], ends[i] })
+	ends = ends[:int(float64(len(ends))*min(max(p, 0), 1)+0.5)]
+
+	knocked := 0
+	for _, c := range ends {
+		// An earlier knock may have opened this one up already.
+		if !g.deadEnd(c) {
+			continue
+		}
+		var walls, fixes []Point
+		for _, d := range orthogonal {
+			w, beyond := Point{c.X + d.X, c.Y + d.Y}, Point{c.X + 2*d.X, c.Y + 2*d.Y}
+			if !g.InBounds(beyond) || !g.IsWall(w) || g.IsWall(beyond) {
+				continue
+			}
+			walls = append(walls, w)
+			if g.deadEnd(beyond) {
+				fixes = append(fixes, w)
+			}
+		}
+		if len(fixes) > 0 {
+			walls = fixes
+		}
+		if len(walls) == 0 {
+			continue
+		}
+		g.SetWall(walls[r.Intn(len(walls))], false)
+		knocked++
+	}
+	return knocked
+}
+
+// deadEnd reports whether c is an open cell with a single open orthogonal
+// neighbour.
+func (g *Graph) deadEnd(c Point) bool {
+	if !g.InBounds(c) || g.IsWall(c) {
+		return false
+	}
+	open := 0
+	for _, d := range orthogonal {
+		if n := (Point{c.X + d.X, c.Y + d.Y}); g.InBounds(n) && !g.IsWall(n) {
+			open++
+		}
+	}
+	return open == 1
+}
@@ -0,0 +1,72 @@
+package dijkstrapf
+
+import "errors"
+
+// ErrBadBridge is returned by AddBridge for ramps that do not lie two or
+// more cells apart in one row or column.
+var ErrBadBridge = errors.New("dijkstrapf: bridge ramps must lie apart in one row or column")
+
+// Bridge is a straight passage between two ramp cells that runs on a level
+// of its own above or below the cells between them, such as a bridge over
+// a river or a tunnel through a ridge. The cells between the ramps keep
+// their ground level, so paths crossing under the bridge and paths taking
+// it do not block each other. The bridge is entered and left only at its
+// ramps.
+type Bridge struct {
+	A, B Point
+}
+
+// Deck returns the cells the bridge passes over, from A towards B.
+func (b Bridge) Deck() []Point {
+	dx, dy := sign(b.B.X-b.A.X), sign(b.B.Y-b.A.Y)
+	var out []Point
+	for p := (Point{b.A.X + dx, b.A.Y + dy}); p != b.B; p = (Point{p.X + dx, p.Y + dy}) {
+		out = append(out, p)
+	}
+	return out
+}
+
+// AddBridge adds a two-way bridge between the ramps a and b. Every step
+// along it costs 1, whatever lies below, so crossing it costs the number
+// of cells between a and b plus one. Like edges added with AddEdge, it
+// only takes effect while both ramps are walkable, and solvers that cannot
+// handle added edges cannot handle bridges either. Paths take a bridge in
+// one move from ramp to ramp; ExpandBridges fills in the deck.
+func (g *Graph) AddBridge(a, b Point) error {
+	if !g.InBounds(a) || !g.InBounds(b) {
+		return ErrOutOfBounds
+	}
+	if a.X != b.X && a.Y != b.Y || abs(a.X-b.X)+abs(a.Y-b.Y) < 2 {
+		return ErrBadBridge
+	}
+	cost := float64(abs(a.X-b.X) + abs(a.Y-b.Y))
+	g.bridges = append(g.bridges, Bridge{a, b})
+	g.addExtraEdge(a, b, cost)
+	g.addExtraEdge(b, a, cost)
+	return nil
+}
+
+// Bridges returns the bridges added to the graph.
+func (g *Graph) Bridges() []Bridge { return append([]Bridge(nil), g.bridges...) }
+
+// ExpandBridges returns p with the deck cells of every bridge it takes
+// inserted between the ramps, for drawing or animating it. The cost is
+// unchanged. The result is no longer a sequence of moves, so solvers and
+// ValidatePath expect the unexpanded path.
+func (g *Graph) ExpandBridges(p Path) Path {
+	if len(g.bridges) == 0 || len(p.Points) == 0 {
+		return p
+	}
+	out := Path{Points: []Point{p.Points[0]}, Cost: p.Cost}
+	for i := 1; i < len(p.Points); i++ {
+		a, b := p.Points[i-1], p.Points[i]
+		for _, br := range g.bridges {
+			if br.A == a && br.B == b || br.A == b && br.B == a {
+				out.Points = append(out.Points, Bridge{a, b}.Deck()...)
+				break
+			}
+		}
+		out.Points = append(out.Points, b)
+	}
+	return out
+}
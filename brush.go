@@ -0,0 +1,84 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// Falloff shapes how the effect of PaintCost fades from the centre of the
+// brush to its rim.
+type Falloff int
+
+const (
+	// FalloffNone paints every cell of the brush in full.
+	FalloffNone Falloff = iota
+	// FalloffLinear fades in a straight line from full at the centre to
+	// nothing at the rim.
+	FalloffLinear
+	// FalloffSmooth fades along a smoothstep curve: flat at the centre and
+	// at the rim, steepest halfway.
+	FalloffSmooth
+)
+
+var falloffNames = []string{"none", "linear", "smooth"}
+
+func (f Falloff) String() string {
+	if f >= 0 && int(f) < len(falloffNames) {
+		return falloffNames[f]
+	}
+	return fmt.Sprintf("Falloff(%d)", int(f))
+}
+
+// scale returns the share of the full effect at t, the distance from the
+// centre over the radius.
+func (f Falloff) scale(t float64) float64 {
+	switch f {
+	case FalloffLinear:
+		return 1 - t
+	case FalloffSmooth:
+		return 1 - t*t*(3-2*t)
+	}
+	return 1
+}
+
+// PaintCost adds delta, faded by falloff, to the weight of every open cell
+// within radius of (cx, cy), as the crow flies. A positive delta raises a
+// bump, such as a danger zone around an enemy; a negative one digs a dip.
+// Painting the same brush again with -delta takes it off again, up to
+// rounding, so a zone can be painted and erased every frame. Terrains are kept. If the
+// brush would bring any weight to zero or below it returns ErrBadWeight
+// and paints nothing.
+func (g *Graph) PaintCost(cx, cy int, radius float64, falloff Falloff, delta float64) error {
+	if !(radius >= 0) || math.IsNaN(delta) {
+		return ErrBadWeight
+	}
+	type stroke struct {
+		p Point
+		w float64
+	}
+	var strokes []stroke
+	r := int(radius)
+	for y := max(cy-r, 0); y <= min(cy+r, g.height-1); y++ {
+		for x := max(cx-r, 0); x <= min(cx+r, g.width-1); x++ {
+			d := math.Hypot(float64(x-cx), float64(y-cy))
+			if d > radius || g.gridMatrix[y][x] == Wall || math.IsInf(g.weights[y][x], 1) {
+				continue
+			}
+			t := 0.0
+			if radius > 0 {
+				t = d / radius
+			}
+			w := g.weights[y][x] + delta*falloff.scale(t)
+			if !(w > 0) {
+				return fmt.Errorf("%w: painting would leave %v at %g", ErrBadWeight, Point{x, y}, w)
+			}
+			strokes = append(strokes, stroke{Point{x, y}, w})
+		}
+	}
+	for _, s := range strokes {
+		g.weights[s.p.Y][s.p.X] = s.w
+		g.stale = true
+		g.changed(ChangeWeight, s.p)
+	}
+	return nil
+}
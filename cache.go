@@ -0,0 +1,242 @@
+package dijkstrapf
+
+import (
+	"container/list"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ErrBadCache is returned by QueryCache.Load for input it cannot read.
+var ErrBadCache = errors.New("dijkstrapf: malformed cache")
+
+// cacheVersion is written by QueryCache.Save and checked by Load.
+const cacheVersion = 1
+
+// Fingerprint returns a hash of everything about g that decides the paths
+// through it: its size, the moves out of every cell and what they cost,
+// and the blockage probabilities. The start and goal are left out. Grids
+// with equal fingerprints give the same paths for the same query, barring
+// a hash collision. It is cached until the graph is next edited.
+func (g *Graph) Fingerprint() uint64 {
+	adj := g.adjacency()
+	if g.fingerprinted {
+		return g.fingerprint
+	}
+	h := fnv.New64a()
+	var buf [8]byte
+	put := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	put(uint64(g.width))
+	put(uint64(g.height))
+	for _, edges := range adj {
+		put(uint64(len(edges)))
+		for _, e := range edges {
+			put(uint64(e.to))
+			put(math.Float64bits(e.cost))
+		}
+	}
+	for _, r := range g.risk {
+		put(math.Float64bits(r))
+	}
+	g.fingerprint, g.fingerprinted = h.Sum64(), true
+	return g.fingerprint
+}
+
+// QueryCache remembers the paths of recent queries, for a server answering
+// many repeated queries on mostly static maps. A query is told apart by its
+// start, goal and algorithm and by the Fingerprint of the grid, so an
+// edited grid misses the cache rather than being served stale paths. The
+// options of a query are not part of its key: use one cache per set of
+// options. When full it forgets the least recently used path. A
+// QueryCache is safe for concurrent use, though the graphs it is given
+// are not.
+type QueryCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[queryKey]*list.Element
+	stats   CacheStats
+}
+
+// CacheStats counts what a QueryCache has done.
+type CacheStats struct {
+	Hits, Misses, Evictions uint64
+	// Len is the number of paths held.
+	Len int
+}
+
+type queryKey struct {
+	start, goal Point
+	algo        string
+	grid        uint64
+}
+
+type queryEntry struct {
+	key  queryKey
+	path Path
+	err  error
+}
+
+// NewQueryCache returns a cache holding up to size paths, at least one.
+func NewQueryCache(size int) *QueryCache {
+	return &QueryCache{size: max(size, 1), order: list.New(), entries: make(map[queryKey]*list.Element)}
+}
+
+// Solve returns the path g.Solve(algo, opts...) gives, from the cache if
+// it holds one. Failing to find a path is remembered too; other errors,
+// such as a cancelled solve, are not. On a hit the graph's last solve and
+// statistics are left as they were.
+func (c *QueryCache) Solve(g *Graph, algo string, opts ...Option) (Path, error) {
+	if _, _, err := g.endpoints(); err != nil {
+		return Path{}, err
+	}
+	key := queryKey{g.start, g.goal, algo, g.Fingerprint()}
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.stats.Hits++
+		e := el.Value.(*queryEntry)
+		c.mu.Unlock()
+		return clonePath(e.path), e.err
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	path, err := g.Solve(algo, opts...)
+	if err != nil && !errors.Is(err, ErrNoPath) {
+		return path, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(&queryEntry{key, clonePath(path), err})
+	return path, err
+}
+
+// Stats returns what the cache has done so far.
+func (c *QueryCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Len = c.order.Len()
+	return s
+}
+
+// Clear forgets every path, keeping the statistics.
+func (c *QueryCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+// savedCache is the JSON form of a QueryCache written by Save.
+type savedCache struct {
+	Version int          `json:"version"`
+	Entries []savedEntry `json:"entries"`
+}
+
+// savedEntry is one path of a savedCache. Grid is the grid's fingerprint in
+// hex; NoPath records a query that found no path.
+type savedEntry struct {
+	Algorithm string   `json:"algorithm"`
+	Grid      string   `json:"grid"`
+	Start     [2]int   `json:"start"`
+	Goal      [2]int   `json:"goal"`
+	NoPath    bool     `json:"no_path,omitempty"`
+	Cost      float64  `json:"cost"`
+	Points    [][2]int `json:"points,omitempty"`
+}
+
+// Save writes the paths in the cache to w as JSON, least recently used
+// first, so that a program run again on the same maps, such as a CLI, can
+// Load them instead of solving again. Statistics are not saved.
+func (c *QueryCache) Save(w io.Writer) error {
+	c.mu.Lock()
+	out := savedCache{Version: cacheVersion}
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		e := el.Value.(*queryEntry)
+		se := savedEntry{
+			Algorithm: e.key.algo,
+			Grid:      strconv.FormatUint(e.key.grid, 16),
+			Start:     [2]int{e.key.start.X, e.key.start.Y},
+			Goal:      [2]int{e.key.goal.X, e.key.goal.Y},
+			NoPath:    e.err != nil,
+			Cost:      e.path.Cost,
+		}
+		for _, p := range e.path.Points {
+			se.Points = append(se.Points, [2]int{p.X, p.Y})
+		}
+		out.Entries = append(out.Entries, se)
+	}
+	c.mu.Unlock()
+	return json.NewEncoder(w).Encode(out)
+}
+
+// Load adds the paths written by Save to the cache, as the most recently
+// used, forgetting older ones if the cache overflows. Since every path is
+// keyed by the fingerprint of its grid, paths saved for other grids, or
+// for a grid since edited, are simply never hit. A query that found no
+// path is loaded with ErrNoPath, without any wrapping it had.
+func (c *QueryCache) Load(r io.Reader) error {
+	var in savedCache
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadCache, err)
+	}
+	if in.Version != cacheVersion {
+		return fmt.Errorf("%w: version %d", ErrBadCache, in.Version)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, se := range in.Entries {
+		grid, err := strconv.ParseUint(se.Grid, 16, 64)
+		if err != nil {
+			return fmt.Errorf("%w: grid %q", ErrBadCache, se.Grid)
+		}
+		e := &queryEntry{key: queryKey{
+			start: Point{se.Start[0], se.Start[1]},
+			goal:  Point{se.Goal[0], se.Goal[1]},
+			algo:  se.Algorithm,
+			grid:  grid,
+		}}
+		e.path.Cost = se.Cost
+		for _, p := range se.Points {
+			e.path.Points = append(e.path.Points, Point{p[0], p[1]})
+		}
+		if se.NoPath {
+			e.err = ErrNoPath
+		}
+		c.put(e)
+	}
+	return nil
+}
+
+// put files e as the most recently used entry, replacing any entry with
+// the same key, and evicts the least recently used ones over the size.
+// c.mu must be held.
+func (c *QueryCache) put(e *queryEntry) {
+	if el, ok := c.entries[e.key]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		delete(c.entries, oldest.Value.(*queryEntry).key)
+		c.order.Remove(oldest)
+		c.stats.Evictions++
+	}
+}
+
+func clonePath(p Path) Path {
+	p.Points = slices.Clone(p.Points)
+	return p
+}
@@ -0,0 +1,129 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"slices"
+	"sort"
+)
+
+// SymbolChokepoint marks chokepoints in WriteChokepoints output.
+const SymbolChokepoint = 'X'
+
+// Chokepoints returns the cells whose removal would disconnect the start
+// from the goal, in grid order. These are the articulation points of the
+// walkable graph that separate the two endpoints; the endpoints themselves
+// are never reported. Edges are treated as two-way. It returns ErrNoPath if
+// the goal is not reachable at all.
+func (g *Graph) Chokepoints() ([]Point, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return nil, err
+	}
+	nb := g.undirected()
+	n := g.nodeCount()
+
+	// Iterative DFS from the start computing discovery times and low links.
+	disc := make([]int, n)
+	low := make([]int, n)
+	parent := make([]int, n)
+	for i := range disc {
+		disc[i] = -1
+	}
+	type frame struct{ node, next int }
+	stack := []frame{{src, 0}}
+	disc[src], parent[src] = 0, -1
+	t := 1
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		u := f.node
+		if f.next < len(nb[u]) {
+			v := nb[u][f.next]
+			f.next++
+			switch {
+			case disc[v] < 0:
+				disc[v], low[v], parent[v] = t, t, u
+				t++
+				stack = append(stack, frame{v, 0})
+			case v != parent[u]:
+				low[u] = min(low[u], disc[v])
+			}
+			continue
+		}
+		if p := parent[u]; p >= 0 {
+			low[p] = min(low[p], low[u])
+		}
+		stack = stack[:len(stack)-1]
+	}
+	if disc[dst] < 0 {
+		return nil, ErrNoPath
+	}
+
+	// Only the tree ancestors of the goal can separate it from the start. An
+	// ancestor p does when the child c leading towards the goal has no back
+	// edge climbing above p.
+	var cut []int
+	for c := dst; parent[c] != src; c = parent[c] {
+		if p := parent[c]; low[c] >= disc[p] {
+			cut = append(cut, p)
+		}
+	}
+	sort.Ints(cut)
+	out := make([]Point, len(cut))
+	for i, id := range cut {
+		out[i] = g.point(id)
+	}
+	return out, nil
+}
+
+// undirected returns, for every walkable cell, the distinct cells it shares
+// an edge with in either direction. Cells that cannot be entered are left
+// out.
+func (g *Graph) undirected() [][]int {
+	adj := g.adjacency()
+	nb := make([][]int, len(adj))
+	link := func(a, b int) {
+		if a == b || slices.Contains(nb[a], b) {
+			return
+		}
+		nb[a] = append(nb[a], b)
+		nb[b] = append(nb[b], a)
+	}
+	for from, edges := range adj {
+		p := g.point(from)
+		if math.IsInf(g.weights[p.Y][p.X], 1) {
+			continue
+		}
+		for _, e := range edges {
+			link(from, e.to)
+		}
+	}
+	return nb
+}
+
+// WriteChokepoints draws the grid like WriteTerrain with the given points
+// marked 'X'. With color set, the marks are drawn in bold red.
+func (g *Graph) WriteChokepoints(w io.Writer, points []Point, color bool) error {
+	mark := make(map[Point]bool, len(points))
+	for _, p := range points {
+		mark[p] = true
+	}
+	bw := bufio.NewWriter(w)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			if mark[p] {
+				writeColored(bw, SymbolChokepoint, "1;31", color)
+				continue
+			}
+			c, err := g.symbol(p)
+			if err != nil {
+				c = '?'
+			}
+			bw.WriteByte(c)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,71 @@
+package dijkstrapf
+
+import "math"
+
+var kingMoves = append(append([]Point(nil), orthogonal...), diagonals...)
+
+// WithClearance makes the solver prefer paths away from walls. Entering a
+// cell costs an extra strength / c, where c is the cell's clearance: the
+// number of king moves to the nearest wall, impassable cell or, on
+// non-wrapping grids, the grid edge. Larger strengths keep agents closer to
+// the middle of corridors at the price of longer paths.
+func WithClearance(strength float64) Option {
+	return func(o *Options) { o.Clearance = strength }
+}
+
+// clearancePenalty returns the extra cost of entering each cell for the
+// given strength.
+func (g *Graph) clearancePenalty(strength float64) []float64 {
+	penalty := make([]float64, g.nodeCount())
+	for id, d := range g.clearance() {
+		if d > 0 {
+			penalty[id] = strength / float64(d)
+		}
+	}
+	return penalty
+}
+
+// clearance returns the clearance of every cell as described at
+// WithClearance, 0 for walls and impassable cells.
+func (g *Graph) clearance() []int {
+	n := g.nodeCount()
+	dist := make([]int, n)
+	var queue []int
+	for id := range dist {
+		p := g.point(id)
+		if g.gridMatrix[p.Y][p.X] == Wall || math.IsInf(g.weights[p.Y][p.X], 1) {
+			queue = append(queue, id)
+		} else {
+			dist[id] = -1
+		}
+	}
+	if !g.wrap {
+		for id := range dist {
+			p := g.point(id)
+			edge := p.X == 0 || p.Y == 0 || p.X == g.width-1 || p.Y == g.height-1
+			if edge && dist[id] < 0 {
+				dist[id] = 1
+				queue = append(queue, id)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		p := g.point(id)
+		for _, d := range kingMoves {
+			q := Point{p.X + d.X, p.Y + d.Y}
+			if g.wrap {
+				q = Point{(q.X + g.width) % g.width, (q.Y + g.height) % g.height}
+			}
+			if !g.InBounds(q) {
+				continue
+			}
+			if qid := g.id(q); dist[qid] < 0 {
+				dist[qid] = dist[id] + 1
+				queue = append(queue, qid)
+			}
+		}
+	}
+	return dist
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["bench"] = command{"benchmark the algorithms on the standard map families", runBench}
+}
+
+// benchResult is one row of bench output. The JSON field names and CSV
+// column order are part of the command's output format.
+type benchResult struct {
+	Family      string `json:"family"`
+	Size        int    `json:"size"`
+	Algorithm   string `json:"algorithm"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+	Expanded    int    `json:"expanded"`
+	Error       string `json:"error,omitempty"`
+}
+
+// benchReport wraps the results with enough context to compare runs across
+// machines and versions.
+type benchReport struct {
+	Time      time.Time     `json:"time"`
+	GoVersion string        `json:"go_version"`
+	GOOS      string        `json:"goos"`
+	GOARCH    string        `json:"goarch"`
+	CPUs      int           `json:"cpus"`
+	Results   []benchResult `json:"results"`
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	families := fs.String("families", strings.Join(dijkstrapf.MapFamilies(), ","), "comma-separated map families")
+	sizes := fs.String("sizes", joinInts(dijkstrapf.BenchSizes), "comma-separated grid sizes")
+	algos := fs.String("algos", strings.Join(dijkstrapf.Algorithms(), ","), "comma-separated algorithms")
+	format := fs.String("format", "table", "output format: table, csv or json")
+	out := fs.String("o", "", "write results to this file instead of stdout")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	sz, err := parseInts(*sizes)
+	if err != nil {
+		return err
+	}
+	write, ok := benchWriters[*format]
+	if !ok {
+		return badArgs("bench: unknown format %q", *format)
+	}
+
+	report := benchReport{
+		Time:      time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+	}
+	for _, family := range strings.Split(*families, ",") {
+		for _, size := range sz {
+			g, err := dijkstrapf.GenerateFamily(family, size, 1)
+			if err != nil {
+				return err
+			}
+			for _, algo := range strings.Split(*algos, ",") {
+				report.Results = append(report.Results, benchOne(g, family, size, algo))
+			}
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return write(w, report)
+}
+
+func benchOne(g *dijkstrapf.Graph, family string, size int, algo string) benchResult {
+	r := benchResult{Family: family, Size: size, Algorithm: algo}
+	if _, err := g.Solve(algo); err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Expanded = g.Stats().Expanded
+	res := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			g.Solve(algo)
+		}
+	})
+	r.NsPerOp = res.NsPerOp()
+	r.AllocsPerOp = res.AllocsPerOp()
+	r.BytesPerOp = res.AllocedBytesPerOp()
+	return r
+}
+
+var benchWriters = map[string]func(io.Writer, benchReport) error{
+	"table": writeBenchTable,
+	"csv":   writeBenchCSV,
+	"json":  writeBenchJSON,
+}
+
+func writeBenchTable(w io.Writer, rep benchReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FAMILY\tSIZE\tALGORITHM\tNS/OP\tALLOCS/OP\tB/OP\tEXPANDED")
+	for _, r := range rep.Results {
+		if r.Error != "" {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", r.Family, r.Size, r.Algorithm, r.Error)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\t%d\t%d\n",
+			r.Family, r.Size, r.Algorithm, r.NsPerOp, r.AllocsPerOp, r.BytesPerOp, r.Expanded)
+	}
+	return tw.Flush()
+}
+
+func writeBenchCSV(w io.Writer, rep benchReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"family", "size", "algorithm", "ns_per_op", "allocs_per_op", "bytes_per_op", "expanded", "error", "go_version", "goos", "goarch"})
+	for _, r := range rep.Results {
+		cw.Write([]string{
+			r.Family, strconv.Itoa(r.Size), r.Algorithm,
+			strconv.FormatInt(r.NsPerOp, 10), strconv.FormatInt(r.AllocsPerOp, 10),
+			strconv.FormatInt(r.BytesPerOp, 10), strconv.Itoa(r.Expanded), r.Error,
+			rep.GoVersion, rep.GOOS, rep.GOARCH,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeBenchJSON(w io.Writer, rep benchReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+func parseInts(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, badArgs("bad number %q", f)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func joinInts(v []int) string {
+	s := make([]string, len(v))
+	for i, n := range v {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["check"] = command{"check that every map in a directory can be solved", runCheck}
+}
+
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	grid := addGridFlags(fs)
+	quiet := fs.Bool("quiet", false, "print only the names of the maps that fail")
+	exts := fs.String("ext", ".map,"+binaryExt, "comma-separated extensions of the map files, with or without "+gzipExt)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return badArgs("check: expected one directory")
+	}
+	names, err := mapFiles(fs.Arg(0), *exts)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if !*quiet {
+		fmt.Fprintln(tw, "MAP\tRESULT\tSTEPS\tCOST")
+	}
+	checked, failed := 0, 0
+	for _, name := range names {
+		checked++
+		result, path := checkMap(filepath.Join(fs.Arg(0), name), grid)
+		switch {
+		case result != "solvable" && *quiet:
+			failed++
+			fmt.Fprintln(tw, name)
+		case result != "solvable":
+			failed++
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\n", name, result)
+		case !*quiet:
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%g\n", name, result, len(path.Points)-1, path.Cost)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !*quiet {
+		fmt.Printf("\n%d maps, %d solvable, %d failed\n", checked, checked-failed, failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("check: %d of %d maps failed", failed, checked)
+	}
+	return nil
+}
+
+// mapFiles returns the names of the regular files in dir whose extension,
+// before any gzipExt, is one of the comma-separated exts, in name order.
+func mapFiles(dir, exts string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	want := strings.Split(exts, ",")
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && slices.Contains(want, filepath.Ext(strings.TrimSuffix(e.Name(), gzipExt))) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// checkMap loads and solves the named map, returning "solvable" and the
+// path, or what is wrong with the map.
+func checkMap(name string, grid gridFlags) (string, dijkstrapf.Path) {
+	g, err := loadMap(name)
+	if err != nil {
+		return "invalid: " + err.Error(), dijkstrapf.Path{}
+	}
+	if err := grid.apply(g); err != nil {
+		return "invalid: " + err.Error(), dijkstrapf.Path{}
+	}
+	path, err := g.FindPath()
+	switch {
+	case err == nil:
+		return "solvable", path
+	case errors.Is(err, dijkstrapf.ErrNoPath):
+		return "unreachable", path
+	case errors.Is(err, dijkstrapf.ErrNoStart):
+		return "no start", path
+	case errors.Is(err, dijkstrapf.ErrNoGoal):
+		return "no goal", path
+	}
+	return "error: " + err.Error(), path
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func init() {
+	commands["chokepoints"] = command{"find the cells every path from start to goal must cross", runChokepoints}
+}
+
+func runChokepoints(args []string) error {
+	fs := flag.NewFlagSet("chokepoints", flag.ContinueOnError)
+	grid := addGridFlags(fs)
+	color := fs.Bool("color", false, "highlight the chokepoints with ANSI colors")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return badArgs("chokepoints: expected one map file")
+	}
+
+	g, err := loadMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := grid.apply(g); err != nil {
+		return err
+	}
+	points, err := g.Chokepoints()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("chokepoints: %d\n\n", len(points))
+	return g.WriteChokepoints(os.Stdout, points, *color)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["compare"] = command{"run several algorithms on one map side by side", runCompare}
+}
+
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	algos := fs.String("algos", strings.Join(dijkstrapf.Algorithms(), ","), "comma-separated algorithms to compare")
+	grid := addGridFlags(fs)
+	overlay := fs.Bool("overlay", false, "also draw the explored areas on the map")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return badArgs("compare: expected one map file")
+	}
+
+	g, err := loadMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := grid.apply(g); err != nil {
+		return err
+	}
+	cmp := g.Compare(strings.Split(*algos, ","))
+	if err := cmp.WriteTable(os.Stdout); err != nil {
+		return err
+	}
+	if *overlay {
+		fmt.Println()
+		return cmp.WriteOverlay(os.Stdout, g)
+	}
+	return nil
+}
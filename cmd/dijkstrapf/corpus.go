@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["corpus"] = command{"generate a reproducible corpus of benchmark maps", runCorpus}
+}
+
+func runCorpus(args []string) error {
+	fs := flag.NewFlagSet("corpus", flag.ContinueOnError)
+	dir := fs.String("dir", "corpus", "directory to write the maps to")
+	families := fs.String("families", strings.Join(dijkstrapf.MapFamilies(), ","), "comma-separated map families")
+	sizes := fs.String("sizes", joinInts(dijkstrapf.BenchSizes), "comma-separated grid sizes")
+	count := fs.Int("count", 1, "maps per family and size")
+	seed := fs.Int64("seed", 1, "seed of the first map; map i uses seed+i")
+	compress := fs.Bool("gzip", false, "compress the maps with gzip")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	sz, err := parseInts(*sizes)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, family := range strings.Split(*families, ",") {
+		for _, size := range sz {
+			for i := 0; i < *count; i++ {
+				s := *seed + int64(i)
+				g, err := dijkstrapf.GenerateFamily(family, size, s)
+				if err != nil {
+					return err
+				}
+				name := filepath.Join(*dir, fmt.Sprintf("%s-%d-%d.map", family, size, s))
+				if *compress {
+					name += gzipExt
+				}
+				if err := writeCorpusMap(name, g, fmt.Sprintf("; family=%s size=%d seed=%d\n", family, size, s), *compress); err != nil {
+					return err
+				}
+				fmt.Println(name)
+			}
+		}
+	}
+	return nil
+}
+
+func writeCorpusMap(name string, g *dijkstrapf.Graph, header string, compress bool) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	write := func(w io.Writer) error {
+		if _, err := io.WriteString(w, header); err != nil {
+			return err
+		}
+		return g.WriteGrid(w)
+	}
+	if compress {
+		err = dijkstrapf.WriteGzip(f, write)
+	} else {
+		err = write(f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
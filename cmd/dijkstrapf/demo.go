@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["demo"] = command{"move the start and goal through a maze and watch it re-solve", runDemo}
+}
+
+const demoHelp = "arrows or h/j/k/l move, tab switches start/goal, n new maze, q quits"
+
+func runDemo(args []string) error {
+	fs := flag.NewFlagSet("demo", flag.ContinueOnError)
+	size := fs.String("size", "61x21", "maze size as `WIDTHxHEIGHT`")
+	seed := fs.Int64("seed", 1, "seed for the first maze")
+	sample := fs.String("sample", "", "start on this bundled sample map instead: "+strings.Join(dijkstrapf.SampleMaps(), ", "))
+	algo := fs.String("algo", "dijkstra", "algorithm to use (see 'dijkstrapf algos')")
+	color := fs.Bool("color", false, "draw the map in ANSI colors")
+	themeName := fs.String("theme", "default", "color theme: default, high-contrast or deuteranopia")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	var width, height int
+	if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return badArgs("demo: bad size %q", *size)
+	}
+	if _, ok := dijkstrapf.Lookup(*algo); !ok {
+		return badArgs("demo: unknown algorithm %q", *algo)
+	}
+	theme, err := findTheme(*themeName)
+	if err != nil {
+		return err
+	}
+
+	g := dijkstrapf.GenerateMaze(width, height, *seed)
+	if *sample != "" {
+		if g, err = dijkstrapf.LoadSample(*sample); err != nil {
+			return err
+		}
+	}
+
+	restore := cbreak()
+	defer restore()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		restore()
+		os.Exit(1)
+	}()
+
+	d := &demo{
+		g:    g,
+		seed: *seed,
+		algo: *algo,
+		ro:   dijkstrapf.RenderOptions{Color: *color, Theme: theme},
+	}
+	d.solve()
+	d.draw(os.Stdout)
+	in := bufio.NewReader(os.Stdin)
+	for {
+		key, err := readKey(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "q":
+			return nil
+		case "\t":
+			d.goal = !d.goal
+		case "n":
+			d.seed++
+			d.g = dijkstrapf.GenerateMaze(width, height, d.seed)
+		case "up", "k":
+			d.move(0, -1)
+		case "down", "j":
+			d.move(0, 1)
+		case "left", "h":
+			d.move(-1, 0)
+		case "right", "l":
+			d.move(1, 0)
+		default:
+			continue
+		}
+		d.solve()
+		d.draw(os.Stdout)
+	}
+}
+
+type demo struct {
+	g    *dijkstrapf.Graph
+	seed int64
+	algo string
+	ro   dijkstrapf.RenderOptions
+	// goal is set while the arrow keys move the goal instead of the start.
+	goal bool
+	path dijkstrapf.Path
+	err  error
+}
+
+// move steps the selected marker by (dx, dy) unless a wall or the other
+// marker is in the way.
+func (d *demo) move(dx, dy int) {
+	p, ok := d.g.Start()
+	if d.goal {
+		p, ok = d.g.Goal()
+	}
+	if !ok {
+		return
+	}
+	q := dijkstrapf.Point{X: p.X + dx, Y: p.Y + dy}
+	if !d.g.InBounds(q) || d.g.Cell(q) != dijkstrapf.Empty {
+		return
+	}
+	if d.goal {
+		d.g.SetGoal(q)
+	} else {
+		d.g.SetStart(q)
+	}
+}
+
+func (d *demo) solve() {
+	d.path, d.err = d.g.Solve(d.algo)
+}
+
+func (d *demo) draw(w io.Writer) {
+	bw := bufio.NewWriter(w)
+	// Home the cursor and clear the screen.
+	bw.WriteString("\x1b[H\x1b[2J")
+	ro := d.ro
+	ro.Path = d.path.Points
+	d.g.Render(bw, ro)
+	moving := "start"
+	if d.goal {
+		moving = "goal"
+	}
+	st := d.g.Stats()
+	if d.err != nil {
+		fmt.Fprintf(bw, "\n%s: %v\n", d.algo, d.err)
+	} else {
+		fmt.Fprintf(bw, "\n%s: cost %g, %d steps, %d expanded in %v\n", d.algo, d.path.Cost, d.path.Len(), st.Expanded, st.Duration)
+	}
+	fmt.Fprintf(bw, "moving the %s; %s\n", moving, demoHelp)
+	bw.Flush()
+}
+
+// readKey reads one key press, naming the arrow keys "up", "down", "left"
+// and "right". Arrow keys arrive as the escape sequences ESC [ A to ESC [ D.
+func readKey(r *bufio.Reader) (string, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	if c != 0x1b {
+		return string(c), nil
+	}
+	seq := make([]byte, 2)
+	if _, err := io.ReadFull(r, seq); err != nil {
+		return "", err
+	}
+	if seq[0] != '[' {
+		return "", nil
+	}
+	switch seq[1] {
+	case 'A':
+		return "up", nil
+	case 'B':
+		return "down", nil
+	case 'C':
+		return "right", nil
+	case 'D':
+		return "left", nil
+	}
+	return "", nil
+}
+
+// cbreak switches the terminal on standard input to deliver key presses
+// without waiting for Enter and without echoing them, using stty(1). It
+// returns a function restoring the previous settings. When standard input
+// is not a terminal, or stty is missing, it does nothing and keys are read
+// a line at a time.
+func cbreak() (restore func()) {
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return func() {}
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return func() {}
+	}
+	return func() { stty(saved) }
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["diff"] = command{"overlay the paths of two algorithms or two versions of a map", runDiff}
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	algoA := fs.String("a", "dijkstra", "algorithm for path a")
+	algoB := fs.String("b", "", "algorithm for path b (default astar, or -a with two maps)")
+	grid := addGridFlags(fs)
+	axes := fs.Bool("axes", false, "number the rows and columns")
+	legend := fs.Bool("legend", false, "explain the symbols below the map")
+	color := fs.Bool("color", false, "draw the map in ANSI colors")
+	themeName := fs.String("theme", "default", "color theme: default, high-contrast or deuteranopia")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 && fs.NArg() != 2 {
+		return badArgs("diff: expected a map file, or two versions of one")
+	}
+	theme, err := findTheme(*themeName)
+	if err != nil {
+		return err
+	}
+
+	// With two maps, path a is solved on the first and both are drawn on
+	// the second.
+	ga, err := loadMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	gb := ga
+	if *algoB == "" {
+		*algoB = "astar"
+		if fs.NArg() == 2 {
+			*algoB = *algoA
+		}
+	}
+	if fs.NArg() == 2 {
+		if gb, err = loadMap(fs.Arg(1)); err != nil {
+			return err
+		}
+		if gb.Width() != ga.Width() || gb.Height() != ga.Height() {
+			return fmt.Errorf("diff: maps differ in size")
+		}
+	}
+	if err := grid.apply(ga); err != nil {
+		return err
+	}
+	if err := grid.apply(gb); err != nil {
+		return err
+	}
+	a, err := ga.Solve(*algoA)
+	if err != nil {
+		return err
+	}
+	b, err := gb.Solve(*algoB)
+	if err != nil {
+		return err
+	}
+	ro := dijkstrapf.RenderOptions{Axes: *axes, Legend: *legend, Color: *color, Theme: theme}
+	return gb.WriteOverlay(os.Stdout, a, b, ro)
+}
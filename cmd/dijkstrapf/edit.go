@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["edit"] = command{"edit a map with commands read from standard input", runEdit}
+}
+
+const editHelp = `commands:
+  wall X Y, clear X Y, weight X Y W, terrain X Y NAME,
+  start X Y, goal X Y, diagonal on|off, corners RULE,
+  diagonal-cost R, wrap on|off,
+  solve [ALGO], show, help, quit`
+
+func runEdit(args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	size := fs.String("size", "16x8", "size of a new map as `WIDTHxHEIGHT`, if no map file is given")
+	record := fs.String("record", "", "append every edit to this journal `file`")
+	replay := fs.String("replay", "", "apply the edits in this journal `file` first")
+	out := fs.String("o", "", "write the edited map to this `file` on quit, in the binary format if it ends in "+binaryExt+" and compressed if it ends in "+gzipExt)
+	quiet := fs.Bool("q", false, "do not redraw the map after every edit")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var g *dijkstrapf.Graph
+	switch fs.NArg() {
+	case 0:
+		var width, height int
+		if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+			return badArgs("edit: bad size %q", *size)
+		}
+		g = dijkstrapf.NewGraph(width, height)
+	case 1:
+		if fs.Arg(0) == stdinName {
+			return badArgs("edit: the map cannot be read from standard input, which carries the commands")
+		}
+		var err error
+		if g, err = loadMap(fs.Arg(0)); err != nil {
+			return err
+		}
+	default:
+		return badArgs("edit: expected at most one map file")
+	}
+
+	if *replay != "" {
+		f, err := os.Open(*replay)
+		if err != nil {
+			return err
+		}
+		err = g.ReplayEdits(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if *record != "" {
+		f, err := os.OpenFile(*record, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		stop := g.RecordEdits(f)
+		defer func() {
+			if err := stop(); err != nil {
+				fmt.Fprintln(os.Stderr, "dijkstrapf: edit: recording:", err)
+			}
+		}()
+	}
+
+	ro := dijkstrapf.RenderOptions{Axes: true}
+	if !*quiet {
+		g.Render(os.Stdout, ro)
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		f := strings.Fields(in.Text())
+		if len(f) == 0 {
+			continue
+		}
+		switch f[0] {
+		case "quit":
+			return saveMap(g, *out)
+		case "help":
+			fmt.Println(editHelp)
+			continue
+		case "show":
+			g.Render(os.Stdout, ro)
+			continue
+		case "solve":
+			algo := "dijkstra"
+			if len(f) > 1 {
+				algo = f[1]
+			}
+			path, err := g.Solve(algo)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			fmt.Printf("cost %g, %d steps: %s\n", path.Cost, path.Len(), path.RunLengthDirections())
+			continue
+		}
+		if err := g.ApplyEdit(in.Text()); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if !*quiet {
+			g.Render(os.Stdout, ro)
+		}
+	}
+	if err := in.Err(); err != nil {
+		return err
+	}
+	return saveMap(g, *out)
+}
+
+// File name extensions saveMap picks the format by: binaryExt for the
+// binary format and gzipExt, after any other, for gzip compression.
+const (
+	binaryExt = ".dpf"
+	gzipExt   = ".gz"
+)
+
+func saveMap(g *dijkstrapf.Graph, name string) error {
+	if name == "" {
+		return nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	write := g.WriteGrid
+	base, compress := strings.CutSuffix(name, gzipExt)
+	if strings.HasSuffix(base, binaryExt) {
+		write = g.WriteBinary
+	}
+	if compress {
+		plain := write
+		write = func(w io.Writer) error { return dijkstrapf.WriteGzip(w, plain) }
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,169 @@
+// Command dijkstrapf solves and inspects grid maps from the command line.
+//
+// Usage:
+//
+//	dijkstrapf <command> [flags] [args]
+//
+// Run "dijkstrapf help" for the list of commands.
+//
+// The exit status tells scripts how a command ended:
+//
+//	0  success
+//	1  any other failure
+//	2  invalid arguments or flags
+//	3  no path between the start and the goal
+//	4  a map that cannot be read or has no start or goal
+//	5  the solve ran out of time
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "dijkstrapf: unknown command %q\n", name)
+		usage()
+		os.Exit(exitUsage)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		msg := err.Error()
+		if !strings.HasPrefix(msg, "dijkstrapf:") {
+			msg = "dijkstrapf: " + msg
+		}
+		fmt.Fprintln(os.Stderr, msg)
+		os.Exit(exitCode(err))
+	}
+}
+
+// Exit statuses; see the package comment.
+const (
+	exitFailure = 1
+	exitUsage   = 2
+	exitNoPath  = 3
+	exitBadMap  = 4
+	exitTimeout = 5
+)
+
+// exitCode returns the exit status for a command that failed with err.
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case errors.As(err, &usage), errors.Is(err, dijkstrapf.ErrUnknownAlgorithm):
+		return exitUsage
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.Is(err, dijkstrapf.ErrNoPath):
+		return exitNoPath
+	case errors.Is(err, dijkstrapf.ErrBadMap), errors.Is(err, dijkstrapf.ErrNoStart), errors.Is(err, dijkstrapf.ErrNoGoal):
+		return exitBadMap
+	}
+	return exitFailure
+}
+
+// usageError is a command line the command cannot make sense of.
+type usageError struct{ err error }
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+// badArgs returns a usageError with the formatted message.
+func badArgs(format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...)}
+}
+
+// parseFlags parses args into fs, making any error a usageError. The flag
+// package has already printed the problem and the flags by then.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dijkstrapf <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].summary)
+	}
+}
+
+// gridFlags are the movement settings shared by the commands that solve.
+type gridFlags struct {
+	fs             *flag.FlagSet
+	diagonal, wrap *bool
+	corners        *string
+	diagonalCost   *float64
+}
+
+func addGridFlags(fs *flag.FlagSet) gridFlags {
+	return gridFlags{
+		fs:           fs,
+		diagonal:     fs.Bool("diagonal", false, "allow diagonal moves"),
+		wrap:         fs.Bool("wrap", false, "wrap around the grid edges"),
+		diagonalCost: fs.Float64("diagonal-cost", 1, "cost of a diagonal step relative to an orthogonal one, such as 1.4142"),
+		corners:      fs.String("corners", "always", "when diagonal moves may cut wall corners: always, one-open or never"),
+	}
+}
+
+// apply changes the settings of g given on the command line, keeping those
+// a binary map file was saved with for the others.
+func (f gridFlags) apply(g *dijkstrapf.Graph) error {
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch fl.Name {
+		case "diagonal":
+			g.SetDiagonal(*f.diagonal)
+		case "wrap":
+			g.SetWrap(*f.wrap)
+		case "diagonal-cost":
+			err = g.SetDiagonalCost(*f.diagonalCost)
+		case "corners":
+			var r dijkstrapf.CornerRule
+			if r, err = dijkstrapf.ParseCornerRule(*f.corners); err == nil {
+				g.SetCornerRule(r)
+			}
+		}
+	})
+	return err
+}
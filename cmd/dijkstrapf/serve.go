@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["serve"] = command{"answer path queries over HTTP on the maps in a directory", runServe}
+}
+
+// maxBatchBody caps the size of a /solve/batch request body in bytes.
+const maxBatchBody = 8 << 20
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	grid := addGridFlags(fs)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	exts := fs.String("ext", ".map,"+binaryExt, "comma-separated extensions of the map files, with or without "+gzipExt)
+	maxQueries := fs.Int("max-queries", 10000, "most queries one batch may hold")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return badArgs("serve: expected one directory of maps")
+	}
+	names, err := mapFiles(fs.Arg(0), *exts)
+	if err != nil {
+		return err
+	}
+	s := &server{grids: make(map[string]*storedGrid), maxQueries: *maxQueries}
+	for _, name := range names {
+		g, err := loadMap(filepath.Join(fs.Arg(0), name))
+		if err != nil {
+			return fmt.Errorf("serve: %s: %w", name, err)
+		}
+		if err := grid.apply(g); err != nil {
+			return err
+		}
+		s.grids[gridName(name)] = &storedGrid{g: g}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /grids", s.handleGrids)
+	mux.HandleFunc("POST /solve/batch", s.handleBatch)
+	log.Printf("serving %d maps on %s", len(s.grids), *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+// gridName is the name a map file is served under: its file name without
+// extensions.
+func gridName(file string) string {
+	if i := strings.IndexByte(file, '.'); i > 0 {
+		return file[:i]
+	}
+	return file
+}
+
+type server struct {
+	grids      map[string]*storedGrid
+	maxQueries int
+}
+
+// storedGrid is a map being served. Solving records its search on the
+// graph, so queries on one grid take turns.
+type storedGrid struct {
+	mu sync.Mutex
+	g  *dijkstrapf.Graph
+}
+
+// batchRequest is the body of POST /solve/batch. The field names are part
+// of the server's interface.
+type batchRequest struct {
+	Grid    string       `json:"grid"`
+	Queries []batchQuery `json:"queries"`
+}
+
+type batchQuery struct {
+	Start jsonPoint `json:"start"`
+	Goal  jsonPoint `json:"goal"`
+}
+
+// batchResponse answers a batchRequest, with one result per query in
+// query order.
+type batchResponse struct {
+	Grid    string        `json:"grid"`
+	Results []batchResult `json:"results"`
+}
+
+type batchResult struct {
+	Found bool        `json:"found"`
+	Cost  *float64    `json:"cost,omitempty"`
+	Path  []jsonPoint `json:"path,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+func (s *server) handleGrids(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(s.grids))
+	for name := range s.grids {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+// handleBatch answers every query of a batch against one stored grid with
+// a single SolveMany call, so that queries sharing a start share a search.
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBody))
+	if err := dec.Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "malformed request: "+err.Error())
+		return
+	}
+	sg, ok := s.grids[req.Grid]
+	if !ok {
+		httpError(w, http.StatusNotFound, fmt.Sprintf("unknown grid %q", req.Grid))
+		return
+	}
+	if len(req.Queries) > s.maxQueries {
+		httpError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("%d queries, at most %d allowed", len(req.Queries), s.maxQueries))
+		return
+	}
+	queries := make([]dijkstrapf.Query, len(req.Queries))
+	for i, q := range req.Queries {
+		queries[i] = dijkstrapf.Query{
+			Start: dijkstrapf.Point{X: q.Start.X, Y: q.Start.Y},
+			Goal:  dijkstrapf.Point{X: q.Goal.X, Y: q.Goal.Y},
+		}
+	}
+	sg.mu.Lock()
+	results := sg.g.SolveMany(queries, dijkstrapf.WithContext(r.Context()))
+	sg.mu.Unlock()
+
+	resp := batchResponse{Grid: req.Grid, Results: make([]batchResult, len(results))}
+	for i, res := range results {
+		out := &resp.Results[i]
+		if res.Err != nil {
+			out.Error = res.Err.Error()
+			continue
+		}
+		out.Found = true
+		out.Cost = &res.Path.Cost
+		out.Path = make([]jsonPoint, len(res.Path.Points))
+		for j, p := range res.Path.Points {
+			out.Path[j] = jsonPoint{p.X, p.Y}
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
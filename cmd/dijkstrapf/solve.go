@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["solve"] = command{"find the shortest path through a map file", runSolve}
+	commands["algos"] = command{"list the available algorithms", runAlgos}
+}
+
+func runSolve(args []string) error {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	algo := fs.String("algo", "dijkstra", "algorithm to use (see 'dijkstrapf algos')")
+	grid := addGridFlags(fs)
+	trace := fs.Bool("trace", false, "print every step of the search to stderr")
+	events := fs.String("events", "", "write every step of the search as JSON lines to this `file`")
+	maxMemory := fs.Int64("max-memory", 0, "fail if the search would need more than this many `bytes`")
+	fallback := fs.Bool("fallback", false, "with -max-memory, fall back to IDA* instead of failing")
+	heatmap := fs.String("heatmap", "", "after solving, draw the distance field: digits, blocks or color")
+	distImage := fs.String("distance-image", "", "after solving, write the distance field as a grey image to this `file`: PGM for .pgm, PNG otherwise")
+	format := fs.String("format", "text", "output format: text or json")
+	timeout := fs.Duration("timeout", 0, "give up if the solve takes longer than this")
+	quiet := fs.Bool("quiet", false, "print only the cost of the path")
+	cacheDir := fs.String("cache", "", "keep the paths found for each map in this `directory` and reuse them on later runs")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return badArgs("solve: expected one map file, or - for standard input")
+	}
+	if *quiet && (*heatmap != "" || *format != "text") {
+		return badArgs("solve: -quiet cannot be used with -heatmap or -format json")
+	}
+	if *cacheDir != "" && (*heatmap != "" || *distImage != "") {
+		return badArgs("solve: -cache cannot be used with -heatmap or -distance-image")
+	}
+	if *format != "text" && *format != "json" {
+		return badArgs("solve: unknown format %q", *format)
+	}
+	if *format == "json" && *heatmap != "" {
+		return badArgs("solve: -heatmap cannot be used with -format json")
+	}
+
+	data, err := readMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	g, err := dijkstrapf.LoadMap(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if err := grid.apply(g); err != nil {
+		return err
+	}
+	var opts []dijkstrapf.Option
+	if *trace {
+		opts = append(opts, dijkstrapf.WithTrace(os.Stderr))
+	}
+	if *events != "" {
+		f, err := os.Create(*events)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w := bufio.NewWriter(f)
+		defer w.Flush()
+		opts = append(opts, dijkstrapf.WithEvents(w))
+	}
+	if *maxMemory > 0 {
+		opts = append(opts, dijkstrapf.WithMemoryLimit(*maxMemory))
+		if *fallback {
+			opts = append(opts, dijkstrapf.WithMemoryFallback())
+		}
+	}
+	if *timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		opts = append(opts, dijkstrapf.WithContext(ctx))
+	}
+	style, ok := heatmapStyles[*heatmap]
+	if *heatmap != "" {
+		if !ok {
+			return badArgs("solve: unknown heatmap style %q", *heatmap)
+		}
+		opts = append(opts, dijkstrapf.WithFullMap())
+	}
+	if *distImage != "" {
+		opts = append(opts, dijkstrapf.WithFullMap())
+	}
+	var path dijkstrapf.Path
+	if *cacheDir != "" {
+		path, err = solveCached(g, *cacheDir, *algo, opts)
+	} else {
+		path, err = g.Solve(*algo, opts...)
+	}
+	if *distImage != "" && err == nil {
+		if err := writeDistanceImage(g, *distImage); err != nil {
+			return err
+		}
+	}
+	if *format == "json" {
+		return writeSolveJSON(os.Stdout, fs.Arg(0), data, *algo, g, path, err)
+	}
+	if err != nil {
+		return err
+	}
+	if *quiet {
+		fmt.Println(path.Cost)
+		return nil
+	}
+
+	fmt.Printf("cost:  %g\n", path.Cost)
+	fmt.Printf("steps: %d\n", path.Len())
+	pts := make([]string, len(path.Points))
+	for i, p := range path.Points {
+		pts[i] = p.String()
+	}
+	fmt.Printf("path:  %s\n", strings.Join(pts, " "))
+	if *heatmap != "" {
+		fmt.Println()
+		return g.WriteHeatmap(os.Stdout, style)
+	}
+	return nil
+}
+
+// solveResult is the output of solve -format json. The field names are part
+// of the command's output format:
+//
+//	map        the map file as given on the command line, "-" for stdin
+//	grid_hash  "sha256:" and the hex SHA-256 digest of the map file
+//	algorithm  the -algo name
+//	width,
+//	height     the size of the grid
+//	found      whether a path was found
+//	cost       the cost of the path, if found
+//	path       the cells of the path from start to goal, if found
+//	stats      the work done: nodes expanded and wall time in nanoseconds
+//	error      the error message, if the solve failed
+type solveResult struct {
+	Map       string      `json:"map"`
+	GridHash  string      `json:"grid_hash"`
+	Algorithm string      `json:"algorithm"`
+	Width     int         `json:"width"`
+	Height    int         `json:"height"`
+	Found     bool        `json:"found"`
+	Cost      *float64    `json:"cost,omitempty"`
+	Path      []jsonPoint `json:"path,omitempty"`
+	Stats     solveStats  `json:"stats"`
+	Error     string      `json:"error,omitempty"`
+}
+
+type jsonPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type solveStats struct {
+	Expanded   int   `json:"expanded"`
+	DurationNs int64 `json:"duration_ns"`
+}
+
+// writeSolveJSON writes the outcome of a solve as a solveResult. A failed
+// solve is still written; its error is returned afterwards so that the
+// command exits with a failure status.
+func writeSolveJSON(w io.Writer, name string, data []byte, algo string, g *dijkstrapf.Graph, path dijkstrapf.Path, solveErr error) error {
+	sum := sha256.Sum256(data)
+	st := g.Stats()
+	res := solveResult{
+		Map:       name,
+		GridHash:  "sha256:" + hex.EncodeToString(sum[:]),
+		Algorithm: algo,
+		Width:     g.Width(),
+		Height:    g.Height(),
+		Stats:     solveStats{st.Expanded, st.Duration.Nanoseconds()},
+	}
+	if solveErr != nil {
+		res.Error = solveErr.Error()
+	} else {
+		res.Found = true
+		res.Cost = &path.Cost
+		res.Path = make([]jsonPoint, len(path.Points))
+		for i, p := range path.Points {
+			res.Path[i] = jsonPoint{p.X, p.Y}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		return err
+	}
+	return solveErr
+}
+
+// cacheSize is the number of paths solve -cache keeps per map.
+const cacheSize = 1024
+
+// solveCached solves g through a query cache kept in dir, in a file named
+// after the grid's fingerprint.
+func solveCached(g *dijkstrapf.Graph, dir, algo string, opts []dijkstrapf.Option) (dijkstrapf.Path, error) {
+	name := filepath.Join(dir, fmt.Sprintf("%016x.json", g.Fingerprint()))
+	c := dijkstrapf.NewQueryCache(cacheSize)
+	if f, err := os.Open(name); err == nil {
+		err = c.Load(f)
+		f.Close()
+		if err != nil {
+			return dijkstrapf.Path{}, err
+		}
+	}
+	path, solveErr := c.Solve(g, algo, opts...)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return path, err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return path, err
+	}
+	if err := c.Save(f); err != nil {
+		f.Close()
+		return path, err
+	}
+	if err := f.Close(); err != nil {
+		return path, err
+	}
+	return path, solveErr
+}
+
+// writeDistanceImage writes the distance field of g to the named file, as
+// a PGM if the name ends in .pgm and as a PNG otherwise.
+func writeDistanceImage(g *dijkstrapf.Graph, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	write := g.WriteDistancePNG
+	if strings.HasSuffix(name, ".pgm") {
+		write = g.WriteDistancePGM
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var heatmapStyles = map[string]dijkstrapf.HeatmapStyle{
+	"digits": dijkstrapf.HeatDigits,
+	"blocks": dijkstrapf.HeatBlocks,
+	"color":  dijkstrapf.HeatColor,
+}
+
+func runAlgos(args []string) error {
+	for _, name := range dijkstrapf.Algorithms() {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// stdinName is the map file name that stands for standard input.
+const stdinName = "-"
+
+// readMap returns the contents of the named map file, or of standard input
+// for stdinName.
+func readMap(name string) ([]byte, error) {
+	if name == stdinName {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// loadMap loads the named map file, or standard input for stdinName, in
+// whatever format it is in.
+func loadMap(name string) (*dijkstrapf.Graph, error) {
+	data, err := readMap(name)
+	if err != nil {
+		return nil, err
+	}
+	return dijkstrapf.LoadMap(bytes.NewReader(data))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func init() {
+	commands["view"] = command{"show a window onto a large map", runView}
+}
+
+func runView(args []string) error {
+	fs := flag.NewFlagSet("view", flag.ContinueOnError)
+	at := fs.String("at", "", "centre the window on `x,y` (default the start)")
+	size := fs.String("size", "80x24", "window size as `WIDTHxHEIGHT`")
+	axes := fs.Bool("axes", false, "number the rows and columns")
+	legend := fs.Bool("legend", false, "explain the symbols below the map")
+	color := fs.Bool("color", false, "draw the map in ANSI colors")
+	themeName := fs.String("theme", "default", "color theme: default, high-contrast or deuteranopia")
+	pan := fs.Bool("pan", false, "read h/j/k/l (H/J/K/L for half a window) from standard input to pan, q to quit")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return badArgs("view: expected one map file")
+	}
+	if *pan && fs.Arg(0) == stdinName {
+		return badArgs("view: -pan reads keys from standard input, so the map cannot come from there")
+	}
+
+	theme, err := findTheme(*themeName)
+	if err != nil {
+		return err
+	}
+	g, err := loadMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var width, height int
+	if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return badArgs("view: bad size %q", *size)
+	}
+	centre, ok := g.Start()
+	if !ok {
+		centre = dijkstrapf.Point{X: g.Width() / 2, Y: g.Height() / 2}
+	}
+	if *at != "" {
+		if _, err := fmt.Sscanf(*at, "%d,%d", &centre.X, &centre.Y); err != nil {
+			return badArgs("view: bad position %q", *at)
+		}
+	}
+
+	v := g.ViewportAt(centre.X, centre.Y, width, height)
+	ro := dijkstrapf.RenderOptions{Viewport: &v, Axes: *axes, Legend: *legend, Color: *color, Theme: theme}
+	if err := writeView(g, ro); err != nil || !*pan {
+		return err
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		for _, c := range in.Text() {
+			dx, dy := 0, 0
+			switch c {
+			case 'h':
+				dx = -1
+			case 'l':
+				dx = 1
+			case 'k':
+				dy = -1
+			case 'j':
+				dy = 1
+			case 'H':
+				dx = -max(1, v.Width/2)
+			case 'L':
+				dx = max(1, v.Width/2)
+			case 'K':
+				dy = -max(1, v.Height/2)
+			case 'J':
+				dy = max(1, v.Height/2)
+			case 'q':
+				return nil
+			}
+			v = g.PanViewport(v, dx, dy)
+		}
+		if err := writeView(g, ro); err != nil {
+			return err
+		}
+	}
+	return in.Err()
+}
+
+func writeView(g *dijkstrapf.Graph, ro dijkstrapf.RenderOptions) error {
+	v := ro.Viewport
+	fmt.Printf("columns %d-%d, rows %d-%d of %dx%d\n", v.X, v.X+v.Width-1, v.Y, v.Y+v.Height-1, g.Width(), g.Height())
+	return g.Render(os.Stdout, ro)
+}
+
+func findTheme(name string) (*dijkstrapf.Theme, error) {
+	for i, t := range dijkstrapf.Themes {
+		if t.Name == name {
+			return &dijkstrapf.Themes[i], nil
+		}
+	}
+	return nil, badArgs("unknown theme %q", name)
+}
@@ -0,0 +1,118 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Comparison holds one row per algorithm run by Compare.
+type Comparison []ComparisonRow
+
+// ComparisonRow is the outcome of one algorithm in a comparison.
+type ComparisonRow struct {
+	Algorithm string
+	Path      Path
+	Stats     Stats
+	Err       error
+
+	explored []bool
+}
+
+// Compare runs every named algorithm on g with the same options and returns
+// their results side by side. A failing algorithm records its error in its
+// row instead of aborting the comparison.
+func (g *Graph) Compare(algos []string, opts ...Option) Comparison {
+	out := make(Comparison, 0, len(algos))
+	for _, name := range algos {
+		row := ComparisonRow{Algorithm: name}
+		row.Path, row.Err = g.Solve(name, opts...)
+		if errors.Is(row.Err, ErrUnknownAlgorithm) {
+			// Nothing ran, so the graph still holds the previous solve.
+			out = append(out, row)
+			continue
+		}
+		row.Stats = g.Stats()
+		if g.closed != nil {
+			row.explored = append([]bool(nil), g.closed...)
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// WriteTable writes an aligned table of cost, path length, turns, expanded
+// nodes and time for each algorithm. Failed algorithms show dashes in the
+// table, and their errors follow it, one per line.
+func (c Comparison) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALGORITHM\tCOST\tSTEPS\tTURNS\tEXPANDED\tTIME")
+	var failed []ComparisonRow
+	for _, r := range c {
+		if r.Err != nil {
+			failed = append(failed, r)
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t%d\t%v\n", r.Algorithm, r.Stats.Expanded, r.Stats.Duration)
+			continue
+		}
+		m := r.Path.Metrics()
+		fmt.Fprintf(tw, "%s\t%g\t%d\t%d\t%d\t%v\n", r.Algorithm, r.Path.Cost, m.Steps, m.Turns, r.Stats.Expanded, r.Stats.Duration)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, r := range failed {
+		if _, err := fmt.Fprintf(w, "%s: %v\n", r.Algorithm, r.Err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteOverlay draws the explored areas of all rows on one copy of g. A cell
+// explored by exactly one algorithm shows that algorithm's letter (A for the
+// first row, B for the second, ...), a cell explored by several shows how
+// many, and unexplored cells show '.'. A legend follows the grid.
+func (c Comparison) WriteOverlay(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			b.WriteByte(c.overlaySymbol(g, Point{x, y}))
+		}
+		b.WriteByte('\n')
+	}
+	for i, r := range c {
+		fmt.Fprintf(&b, "%c = %s\n", 'A'+i, r.Algorithm)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c Comparison) overlaySymbol(g *Graph, p Point) byte {
+	switch g.Cell(p) {
+	case Wall:
+		return SymbolWall
+	case Start:
+		return SymbolStart
+	case Goal:
+		return SymbolGoal
+	}
+	id := g.id(p)
+	count, who := 0, 0
+	for i, r := range c {
+		if id < len(r.explored) && r.explored[id] {
+			count++
+			who = i
+		}
+	}
+	switch {
+	case count == 0:
+		return SymbolEmpty
+	case count == 1 && who < 26:
+		return byte('A' + who)
+	case count > 9:
+		return '+'
+	}
+	return byte('0' + count)
+}
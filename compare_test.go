@@ -0,0 +1,25 @@
+package dijkstrapf_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteTableFailure(t *testing.T) {
+	g := loadRenderMap(t)
+	var b strings.Builder
+	if err := g.Compare([]string{"dijkstra", "nope"}).WriteTable(&b); err != nil {
+		t.Fatal(err)
+	}
+	// Durations vary from run to run.
+	got := regexp.MustCompile(`(?m)[0-9.]+(ns|µs|ms|s)$`).ReplaceAllString(b.String(), "T")
+	want := `ALGORITHM  COST  STEPS  TURNS  EXPANDED  TIME
+dijkstra   19    18     5      30        T
+nope       -     -      -      0         T
+nope: dijkstrapf: unknown algorithm: "nope"
+`
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
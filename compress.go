@@ -0,0 +1,43 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// LoadMap reads a map in any format this package writes: the text format
+// of LoadGrid, the binary format of LoadBinary, or either of them
+// compressed with gzip. The format is detected from the first bytes.
+func LoadMap(r io.Reader) (*Graph, error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(gzipMagic)); bytes.Equal(head, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+	if head, _ := br.Peek(len(binaryMagic)); IsBinary(head) {
+		return LoadBinary(br)
+	}
+	return LoadGrid(br)
+}
+
+// WriteGzip compresses what write writes to w with gzip, such as
+//
+//	dijkstrapf.WriteGzip(f, g.WriteBinary)
+//
+// LoadMap reads the result back.
+func WriteGzip(w io.Writer, write func(io.Writer) error) error {
+	zw := gzip.NewWriter(w)
+	if err := write(zw); err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,78 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// CornerRule decides when a diagonal move may pass the corner of a wall.
+// It only matters with diagonal moves enabled.
+type CornerRule int
+
+const (
+	// CornerAlways allows every diagonal move between walkable cells,
+	// even squeezing between two walls that touch at their corners.
+	CornerAlways CornerRule = iota
+	// CornerOneOpen allows a diagonal move if at least one of the two
+	// cells it passes beside is walkable.
+	CornerOneOpen
+	// CornerNever allows a diagonal move only if both cells it passes
+	// beside are walkable, so paths never clip a wall's corner.
+	CornerNever
+)
+
+var cornerRuleNames = []string{"always", "one-open", "never"}
+
+func (r CornerRule) String() string {
+	if r >= 0 && int(r) < len(cornerRuleNames) {
+		return cornerRuleNames[r]
+	}
+	return fmt.Sprintf("CornerRule(%d)", int(r))
+}
+
+// ParseCornerRule returns the rule with the given String form.
+func ParseCornerRule(s string) (CornerRule, error) {
+	for i, name := range cornerRuleNames {
+		if s == name {
+			return CornerRule(i), nil
+		}
+	}
+	return 0, fmt.Errorf("dijkstrapf: unknown corner rule %q", s)
+}
+
+// CornerRule returns the corner-cutting rule for diagonal moves.
+func (g *Graph) CornerRule() CornerRule { return g.corners }
+
+// SetCornerRule sets the corner-cutting rule for diagonal moves. The
+// default is CornerAlways.
+func (g *Graph) SetCornerRule(r CornerRule) {
+	g.corners = r
+	g.stale = true
+	g.changed(ChangeLayout, Point{-1, -1})
+}
+
+// cornerAllowed reports whether the diagonal move d from p obeys the
+// corner rule.
+func (g *Graph) cornerAllowed(p, d Point) bool {
+	if g.corners == CornerAlways {
+		return true
+	}
+	open := 0
+	for _, q := range []Point{{p.X + d.X, p.Y}, {p.X, p.Y + d.Y}} {
+		if g.wrap {
+			q = Point{(q.X + g.width) % g.width, (q.Y + g.height) % g.height}
+		}
+		if g.open(q) {
+			open++
+		}
+	}
+	if g.corners == CornerOneOpen {
+		return open >= 1
+	}
+	return open == 2
+}
+
+// open reports whether q can be entered at all.
+func (g *Graph) open(q Point) bool {
+	return g.InBounds(q) && !g.IsWall(q) && !math.IsInf(g.weights[q.Y][q.X], 1)
+}
@@ -0,0 +1,107 @@
+package dijkstrapf
+
+import "math"
+
+// FillDeadEnds prunes the cells that cannot lie on any path from the start
+// to the goal: dead-end corridors, filled in from their closed ends, and
+// areas not connected to the start at all. The solvers that use the
+// adjacency list then skip those cells; JPS walks the grid directly and
+// ignores the pruning. It returns the number of cells pruned. The pruning
+// lasts until the grid is next edited, including moving the start or goal.
+func (g *Graph) FillDeadEnds() (int, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return 0, err
+	}
+	g.buildAdjacency()
+	nb := g.undirected()
+	n := g.nodeCount()
+	pruned := make([]bool, n)
+	open := func(id int) bool {
+		p := g.point(id)
+		return !pruned[id] && !g.IsWall(p) && !math.IsInf(g.weights[p.Y][p.X], 1)
+	}
+
+	// Peel cells with at most one open neighbour until none are left.
+	deg := make([]int, n)
+	var queue []int
+	for id := range nb {
+		if !open(id) {
+			continue
+		}
+		deg[id] = len(nb[id])
+		if deg[id] <= 1 && id != src && id != dst {
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if pruned[id] {
+			continue
+		}
+		pruned[id] = true
+		for _, v := range nb[id] {
+			deg[v]--
+			if deg[v] == 1 && open(v) && v != src && v != dst {
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	// Everything still open but out of reach of the start goes too.
+	seen := make([]bool, n)
+	seen[src] = true
+	queue = append(queue, src)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, v := range nb[id] {
+			if !seen[v] && !pruned[v] {
+				seen[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	count := 0
+	for id := range pruned {
+		if open(id) && !seen[id] {
+			pruned[id] = true
+		}
+		if pruned[id] {
+			count++
+		}
+	}
+
+	adj := g.adjList
+	for id, edges := range adj {
+		if pruned[id] {
+			adj[id] = nil
+			continue
+		}
+		kept := edges[:0]
+		for _, e := range edges {
+			if !pruned[e.to] {
+				kept = append(kept, e)
+			}
+		}
+		adj[id] = kept
+	}
+	g.pruned = pruned
+	return count, nil
+}
+
+// DeadEnds returns the cells pruned by the last FillDeadEnds, in grid
+// order, or nil if the grid has been edited since.
+func (g *Graph) DeadEnds() []Point {
+	if g.pruned == nil || g.stale {
+		return nil
+	}
+	var out []Point
+	for id, ok := range g.pruned {
+		if ok {
+			out = append(out, g.point(id))
+		}
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package dijkstrapf
+
+import "math"
+
+// Difficulty measures how hard a maze is to solve by hand. Edges are
+// treated as two-way and weights are ignored: it is about the shape of the
+// maze, not the cost of walking it.
+type Difficulty struct {
+	// Steps is the number of moves on the shortest solution.
+	Steps int
+	// Tortuosity is Steps over the number of moves the solution would take
+	// on an empty grid, 1 for a straight run.
+	Tortuosity float64
+	// Branching is the mean number of neighbours of the walkable cells.
+	Branching float64
+	// DeadEnds counts the walkable cells with a single neighbour, other
+	// than the start and goal.
+	DeadEnds int
+	// Decisions counts the cells of the solution where a way on does not
+	// lead any closer to the goal, and WrongTurns counts those ways.
+	Decisions  int
+	WrongTurns int
+	// Score sums up the measures as Tortuosity times the base-2 logarithm
+	// of one plus WrongTurns, plus one: 1 for a corridor, growing with
+	// every wrong turn the solution passes and with how far it winds. It
+	// is meant for sorting mazes into buckets, not as an absolute scale.
+	Score float64
+}
+
+// Difficulty scores the maze between the start and the goal. It returns
+// ErrNoPath if the goal cannot be reached.
+func (g *Graph) Difficulty() (Difficulty, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Difficulty{}, err
+	}
+	nb := g.undirected()
+
+	// Search from the goal, so that next leads along a shortest solution
+	// and dist tells which ways on lead closer to the goal.
+	next := make([]int, len(nb))
+	dist := make([]int, len(nb))
+	for i := range next {
+		next[i] = -1
+	}
+	next[dst] = dst
+	for queue := []int{dst}; len(queue) > 0; {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, v := range nb[cur] {
+			if next[v] < 0 {
+				next[v], dist[v] = cur, dist[cur]+1
+				queue = append(queue, v)
+			}
+		}
+	}
+	if next[src] < 0 {
+		return Difficulty{}, ErrNoPath
+	}
+
+	var d Difficulty
+	cells, degrees := 0, 0
+	for id, n := range nb {
+		p := g.point(id)
+		if g.IsWall(p) || math.IsInf(g.weights[p.Y][p.X], 1) {
+			continue
+		}
+		cells++
+		degrees += len(n)
+		if len(n) == 1 && id != src && id != dst {
+			d.DeadEnds++
+		}
+	}
+	if cells > 0 {
+		d.Branching = float64(degrees) / float64(cells)
+	}
+
+	for from, v := -1, src; v != dst; from, v = v, next[v] {
+		wrong := 0
+		for _, w := range nb[v] {
+			if w != from && dist[w] >= dist[v] {
+				wrong++
+			}
+		}
+		if wrong > 0 {
+			d.Decisions++
+			d.WrongTurns += wrong
+		}
+		d.Steps++
+	}
+	d.Tortuosity = 1
+	if straight := g.moves(g.start, g.goal); straight > 0 {
+		d.Tortuosity = float64(d.Steps) / straight
+	}
+	d.Score = 1 + d.Tortuosity*math.Log2(1+float64(d.WrongTurns))
+	return d, nil
+}
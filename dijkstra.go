@@ -0,0 +1,161 @@
+package dijkstrapf
+
+import "math"
+
+// FindPath runs Dijkstra's algorithm from the start to the goal cell.
+// It returns ErrNoPath if the goal cannot be reached.
+func (g *Graph) FindPath(opts ...Option) (Path, error) {
+	return g.run("dijkstra", dijkstra, opts)
+}
+
+func (g *Graph) endpoints() (int, int, error) {
+	if !g.hasStart {
+		return 0, 0, ErrNoStart
+	}
+	if !g.hasGoal {
+		return 0, 0, ErrNoGoal
+	}
+	return g.id(g.start), g.id(g.goal), nil
+}
+
+// resetSearch allocates fresh dist and prev arrays and stores them, with
+// the explored set and statistics, as the graph's latest search result.
+func (g *Graph) resetSearch() ([]float64, []int) {
+	n := g.nodeCount()
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	g.dist, g.prev = dist, prev
+	g.closed = make([]bool, n)
+	g.stats = Stats{}
+	g.reversed = false
+	return dist, prev
+}
+
+// WithFullMap makes Dijkstra's algorithm keep going after reaching the goal
+// until every reachable cell is settled, leaving a complete distance field
+// on the graph. In this mode a goal is optional; without one FindPath
+// returns an empty path and no error.
+func WithFullMap() Option {
+	return func(o *Options) { o.FullMap = true }
+}
+
+// WithReverse makes Dijkstra's algorithm search outward from the goal over
+// reversed edges, so the distance field measures the cost of reaching the
+// goal from each cell. Combined with WithFullMap and PathTo, one solve
+// serves any number of starts heading for the same goal. In full-map mode
+// the start is optional.
+func WithReverse() Option {
+	return func(o *Options) { o.Reverse = true }
+}
+
+func dijkstra(g *Graph, o *Options) (Path, error) {
+	if o.FullMap && o.Reverse && g.hasGoal && !g.hasStart {
+		_, _, err := g.search(o, g.id(g.goal), nil)
+		return Path{}, err
+	}
+	if o.FullMap && !o.Reverse && g.hasStart && !g.hasGoal {
+		_, _, err := g.search(o, g.id(g.start), nil)
+		return Path{}, err
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	if o.Reverse {
+		src, dst = dst, src
+	}
+	stop := func(node int) bool { return node == dst }
+	if o.FullMap {
+		stop = nil
+	}
+	dist, prev, err := g.search(o, src, stop)
+	if err != nil {
+		if dist != nil {
+			return g.partial(o, dist, prev, err)
+		}
+		return Path{}, err
+	}
+	g.finish(o, dst, dist[dst])
+	if math.IsInf(dist[dst], 1) {
+		return g.partial(o, dist, prev, ErrNoPath)
+	}
+	if o.Reverse {
+		return Path{Points: g.walk(prev, dst), Cost: dist[dst]}, nil
+	}
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, nil
+}
+
+// search runs Dijkstra's algorithm from src until stop reports true for a
+// settled node, or until every reachable node is settled if stop is nil.
+// With o.Reverse set it follows edges backwards, so prev points towards
+// src rather than away from it.
+func (g *Graph) search(o *Options, src int, stop func(node int) bool) ([]float64, []int, error) {
+	adj := g.adjacency()
+	if o.Reverse {
+		adj = g.reverseAdjacency()
+	}
+	n := g.nodeCount()
+	dist, prev := g.resetSearch()
+	g.reversed = o.Reverse
+	dist[src] = 0
+	pq := g.frontier(o, n, prev)
+	pq.Push(src, 0)
+	for pq.Len() > 0 {
+		cur, d := pq.PopMin()
+		g.settle(o, cur, d)
+		if stop != nil && stop(cur) {
+			break
+		}
+		if err := g.cancelled(o); err != nil {
+			return dist, prev, err
+		}
+		for _, e := range adj[cur] {
+			var c float64
+			var err error
+			if o.Reverse {
+				c, err = g.stepCost(o, e.to, edge{cur, e.cost})
+			} else {
+				c, err = g.stepCost(o, cur, e)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			nd := d + c
+			if nd == dist[e.to] && !g.closed[e.to] && g.preferParent(o, prev, e.to, cur) {
+				prev[e.to] = cur
+				pq.DecreaseKey(e.to, nd)
+				continue
+			}
+			if nd >= dist[e.to] {
+				continue
+			}
+			g.relax(o, e.to, dist[e.to], nd)
+			fresh := math.IsInf(dist[e.to], 1)
+			dist[e.to] = nd
+			prev[e.to] = cur
+			if fresh {
+				pq.Push(e.to, nd)
+			} else {
+				pq.DecreaseKey(e.to, nd)
+			}
+		}
+	}
+	return dist, prev, nil
+}
+
+// reverseAdjacency returns the adjacency list with every edge flipped,
+// keeping the cost of the original direction.
+func (g *Graph) reverseAdjacency() [][]edge {
+	adj := g.adjacency()
+	rev := make([][]edge, len(adj))
+	for from, edges := range adj {
+		for _, e := range edges {
+			rev[e.to] = append(rev[e.to], edge{from, e.cost})
+		}
+	}
+	return rev
+}
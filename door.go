@@ -0,0 +1,155 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoDoor is returned when opening, closing or removing a door where
+// there is none.
+var ErrNoDoor = errors.New("dijkstrapf: no door at that cell")
+
+// Door symbols drawn by Render.
+const (
+	SymbolDoorOpen   = '/'
+	SymbolDoorClosed = '+'
+	SymbolDoorLocked = '&'
+)
+
+// DoorState says whether a door can be walked through.
+type DoorState int
+
+const (
+	// DoorOpen lets paths through at the cell's weight.
+	DoorOpen DoorState = iota
+	// DoorClosed lets paths through at the cell's weight plus the door's
+	// opening cost, the time it takes to open it on the way.
+	DoorClosed
+	// DoorLocked blocks the cell like a wall until the door is opened
+	// with OpenDoor.
+	DoorLocked
+)
+
+var doorStateNames = []string{"open", "closed", "locked"}
+
+func (s DoorState) String() string {
+	if s >= 0 && int(s) < len(doorStateNames) {
+		return doorStateNames[s]
+	}
+	return fmt.Sprintf("DoorState(%d)", int(s))
+}
+
+// door is the state of one door cell.
+type door struct {
+	state DoorState
+	cost  float64
+}
+
+// SetDoor puts a door in state on p, replacing a wall there. A closed door
+// adds openCost to every move into p; an open one adds nothing and a
+// locked one blocks it.
+func (g *Graph) SetDoor(p Point, state DoorState, openCost float64) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	if openCost < 0 || math.IsNaN(openCost) {
+		return ErrNegativeCost
+	}
+	if g.gridMatrix[p.Y][p.X] == Wall {
+		g.gridMatrix[p.Y][p.X] = Empty
+	}
+	if g.doors == nil {
+		g.doors = make(map[int]door)
+	}
+	g.doors[g.id(p)] = door{state, openCost}
+	delete(g.locks, g.id(p))
+	g.stale = true
+	g.changed(ChangeDoor, p)
+	return nil
+}
+
+// Door returns the state of the door on p, if there is one.
+func (g *Graph) Door(p Point) (DoorState, bool) {
+	if !g.InBounds(p) {
+		return 0, false
+	}
+	d, ok := g.doors[g.id(p)]
+	return d.state, ok
+}
+
+// OpenDoor opens the door on p, unlocking it if need be.
+func (g *Graph) OpenDoor(p Point) error {
+	return g.setDoorState(p, DoorOpen)
+}
+
+// CloseDoor closes the door on p without locking it.
+func (g *Graph) CloseDoor(p Point) error {
+	return g.setDoorState(p, DoorClosed)
+}
+
+// RemoveDoor takes the door off p, leaving the ground below.
+func (g *Graph) RemoveDoor(p Point) error {
+	if _, ok := g.Door(p); !ok {
+		return ErrNoDoor
+	}
+	delete(g.doors, g.id(p))
+	delete(g.locks, g.id(p))
+	g.stale = true
+	g.changed(ChangeDoor, p)
+	return nil
+}
+
+func (g *Graph) setDoorState(p Point, state DoorState) error {
+	if _, ok := g.Door(p); !ok {
+		return ErrNoDoor
+	}
+	d := g.doors[g.id(p)]
+	d.state = state
+	g.doors[g.id(p)] = d
+	g.stale = true
+	g.changed(ChangeDoor, p)
+	return nil
+}
+
+// doorCost returns what the door on node adds to the cost of entering it:
+// nothing without a door, +Inf for a locked one.
+func (g *Graph) doorCost(node int) float64 {
+	d, ok := g.doors[node]
+	switch {
+	case !ok || d.state == DoorOpen:
+		return 0
+	case d.state == DoorLocked:
+		return math.Inf(1)
+	}
+	return d.cost
+}
+
+// shutDoors reports whether any door is closed or locked.
+func (g *Graph) shutDoors() bool {
+	for _, d := range g.doors {
+		if d.state != DoorOpen {
+			return true
+		}
+	}
+	return false
+}
+
+// doorSymbol returns the symbol of the door on p, if there is one.
+func (g *Graph) doorSymbol(p Point) (byte, bool) {
+	s, ok := g.Door(p)
+	if !ok {
+		return 0, false
+	}
+	return [...]byte{SymbolDoorOpen, SymbolDoorClosed, SymbolDoorLocked}[s], true
+}
+
+// throughDoor prices an added edge e with the door it leads into, and
+// reports whether it can be taken at all.
+func (g *Graph) throughDoor(e edge) (edge, bool) {
+	if g.IsWall(g.point(e.to)) {
+		return e, false
+	}
+	e.cost += g.doorCost(e.to)
+	return e, !math.IsInf(e.cost, 1)
+}
@@ -0,0 +1,312 @@
+package dijkstrapf
+
+import (
+	"math"
+	"time"
+)
+
+// AnytimeDStar is an Anytime D* planner. Like FindPathAnytime it first
+// finds a path with an inflated heuristic and then improves it while time
+// remains, and like D* it repairs its search, rather than starting over,
+// when the grid changes under it or the agent moves. This makes it the
+// planner of choice for a robot that keeps planning on a map it keeps
+// learning.
+//
+// The search runs backwards from the goal, so that moving the start is
+// cheap: call the graph's SetStart as the agent moves. Walls, weights and
+// terrains, doors and risks may be edited between calls to Plan; the planner learns of the
+// edits through OnChange and repairs the costs around them. Moving the
+// goal, changing a setting of the whole grid, such as diagonal moves, or
+// adding and removing edges makes it start over. Call Close when done with
+// the planner to stop it observing the graph.
+type AnytimeDStar struct {
+	g         *Graph
+	o         *Options
+	h         HeuristicFunc
+	eps, eps0 float64
+	start     int
+	goal      int
+	adj       [][]edge
+	pred      [][]edge
+
+	// cost is the cost of reaching the goal from each cell as of the last
+	// expansion, rhs its one-step lookahead.
+	cost     []float64
+	rhs      []float64
+	open     *BinaryHeap
+	closed   []bool
+	inIncons []bool
+	incons   []int
+
+	edited   []Point
+	relayout bool
+	cancel   func()
+	err      error
+	expanded int
+}
+
+// NewAnytimeDStar returns a planner from the start of g to its goal that
+// prices steps with opts. The first plan uses the heuristic inflated by the
+// epsilon set with WithEpsilon, or 3 if none is set. WithQueue is ignored.
+func NewAnytimeDStar(g *Graph, opts ...Option) (*AnytimeDStar, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return nil, err
+	}
+	o := buildOptions(opts)
+	if o.Reverse {
+		return nil, errForwardOnly
+	}
+	d := &AnytimeDStar{g: g, o: o, eps0: 3, start: src, goal: dst}
+	if o.Epsilon > 1 {
+		d.eps0 = o.Epsilon
+	}
+	d.cancel = g.OnChange(d.observe)
+	d.reset()
+	return d, nil
+}
+
+// Close stops the planner observing the graph. It must not be used after.
+func (d *AnytimeDStar) Close() {
+	d.cancel()
+}
+
+// Expanded returns the number of cells expanded so far over all plans.
+func (d *AnytimeDStar) Expanded() int { return d.expanded }
+
+func (d *AnytimeDStar) observe(c Change) {
+	switch c.Kind {
+	case ChangeWall, ChangeWeight, ChangeTerrain, ChangeDoor, ChangeRisk:
+		d.edited = append(d.edited, c.At)
+	case ChangeKey:
+		// Only FindPathKeys picks keys up.
+	case ChangeMarker:
+		if !d.g.hasGoal || d.g.id(d.g.goal) != d.goal {
+			d.relayout = true
+		}
+	default:
+		d.relayout = true
+	}
+}
+
+// reset forgets the search and plans from scratch with the initial
+// inflation.
+func (d *AnytimeDStar) reset() {
+	g := d.g
+	n := g.nodeCount()
+	d.adj, d.pred = g.adjacency(), g.reverseAdjacency()
+	if g.hasGoal {
+		d.goal = g.id(g.goal)
+	}
+	d.o.penalty = nil
+	d.h = g.heuristic(d.o)
+	d.cost, d.rhs = make([]float64, n), make([]float64, n)
+	for i := range d.cost {
+		d.cost[i], d.rhs[i] = math.Inf(1), math.Inf(1)
+	}
+	d.open = NewBinaryHeap(n)
+	d.open.tie = make([]float64, n)
+	d.closed, d.inIncons, d.incons = make([]bool, n), make([]bool, n), nil
+	d.eps = d.eps0
+	d.rhs[d.goal] = 0
+	d.push(d.goal)
+	d.edited, d.relayout = nil, false
+}
+
+// Plan brings the plan up to date with the edits made since the last call
+// and improves it until it is optimal or the deadline passes, returning
+// the best path from the start to the goal and the bound on how far its
+// cost may be from the optimum. A zero deadline means no deadline. The
+// first path of every call is always completed, whatever the deadline.
+// After edits the inflation goes back up to its initial value, so that the
+// repair is quick, and comes down again with the time left.
+func (d *AnytimeDStar) Plan(deadline time.Time) (Path, float64, error) {
+	g := d.g
+	if _, _, err := g.endpoints(); err != nil {
+		return Path{}, math.Inf(1), err
+	}
+	d.start = g.id(g.start)
+	switch {
+	case d.relayout || len(d.edited) > 0 && d.o.Clearance > 0:
+		d.reset()
+	case len(d.edited) > 0:
+		d.repair()
+		d.eps = d.eps0
+	}
+
+	var best Path
+	bound := math.Inf(1)
+	for first := true; ; first = false {
+		d.requeue()
+		expired := d.improve(deadline, first)
+		if d.err != nil {
+			err := d.err
+			d.err = nil
+			return Path{}, math.Inf(1), err
+		}
+		if expired {
+			break
+		}
+		if math.IsInf(d.cost[d.start], 1) {
+			return Path{}, math.Inf(1), ErrNoPath
+		}
+		best, bound = d.path(), d.eps
+		if d.eps <= 1 {
+			break
+		}
+		d.eps = max(1, d.eps-0.5)
+	}
+	return best, bound, nil
+}
+
+// repair takes in the edited cells: every cell whose moves may have
+// changed gets its lookahead recomputed.
+func (d *AnytimeDStar) repair() {
+	g := d.g
+	oldPred := d.pred
+	d.adj, d.pred = g.adjacency(), g.reverseAdjacency()
+	// A lighter cell may have lowered the least weight the heuristic
+	// relies on.
+	d.h = g.heuristic(d.o)
+	touched := make(map[int]bool)
+	for _, p := range d.edited {
+		v := g.id(p)
+		for _, e := range oldPred[v] {
+			touched[e.to] = true
+		}
+		for _, e := range d.pred[v] {
+			touched[e.to] = true
+		}
+		// A wall also decides which diagonal moves around it cut a corner.
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				q := Point{p.X + dx, p.Y + dy}
+				if g.wrap {
+					q = Point{(q.X + g.width) % g.width, (q.Y + g.height) % g.height}
+				}
+				if g.InBounds(q) {
+					touched[g.id(q)] = true
+				}
+			}
+		}
+	}
+	for v := range touched {
+		d.update(v)
+	}
+	d.edited = nil
+}
+
+// key returns the priority of v in the open list, compared first by k1 and
+// then by k2.
+func (d *AnytimeDStar) key(v int) (k1, k2 float64) {
+	h := d.h(d.g.point(d.start), d.g.point(v))
+	if d.cost[v] > d.rhs[v] {
+		return d.rhs[v] + d.eps*h, d.rhs[v]
+	}
+	return d.cost[v] + h, d.cost[v]
+}
+
+func (d *AnytimeDStar) push(v int) {
+	k1, k2 := d.key(v)
+	d.open.tie[v] = k2
+	d.open.Push(v, k1)
+}
+
+// update recomputes the lookahead of v and files v as consistent, open,
+// or inconsistent until the next iteration.
+func (d *AnytimeDStar) update(v int) {
+	if v != d.goal {
+		d.rhs[v] = math.Inf(1)
+		for _, e := range d.adj[v] {
+			c, err := d.g.stepCost(d.o, v, e)
+			if err != nil {
+				d.err = err
+				continue
+			}
+			d.rhs[v] = min(d.rhs[v], c+d.cost[e.to])
+		}
+	}
+	d.open.remove(v)
+	if d.cost[v] == d.rhs[v] {
+		return
+	}
+	if !d.closed[v] {
+		d.push(v)
+	} else if !d.inIncons[v] {
+		d.inIncons[v] = true
+		d.incons = append(d.incons, v)
+	}
+}
+
+// requeue starts an iteration: the inconsistent cells go back to the open
+// list, every open cell is keyed for the current inflation and start, and
+// nothing is closed.
+func (d *AnytimeDStar) requeue() {
+	var queued []int
+	for d.open.Len() > 0 {
+		v, _ := d.open.PopMin()
+		queued = append(queued, v)
+	}
+	for _, v := range d.incons {
+		d.inIncons[v] = false
+		queued = append(queued, v)
+	}
+	d.incons = d.incons[:0]
+	for _, v := range queued {
+		d.push(v)
+	}
+	clear(d.closed)
+}
+
+// improve expands cells until the start is consistent and no open cell
+// has a smaller key. Unless first is set it gives up once the deadline
+// passes and reports so.
+func (d *AnytimeDStar) improve(deadline time.Time, first bool) bool {
+	for expansions := 0; d.open.Len() > 0; expansions++ {
+		if !first && !deadline.IsZero() && expansions%256 == 0 && time.Now().After(deadline) {
+			return true
+		}
+		v, k1 := d.open.PopMin()
+		k2 := d.open.tie[v]
+		s1, s2 := d.key(d.start)
+		if (k1 > s1 || k1 == s1 && k2 >= s2) && d.cost[d.start] == d.rhs[d.start] {
+			d.push(v)
+			return false
+		}
+		d.expanded++
+		if d.cost[v] > d.rhs[v] {
+			d.cost[v] = d.rhs[v]
+			d.closed[v] = true
+		} else {
+			d.cost[v] = math.Inf(1)
+			d.update(v)
+		}
+		for _, e := range d.pred[v] {
+			d.update(e.to)
+		}
+	}
+	return false
+}
+
+// path follows the cheapest moves from the start down to the goal.
+func (d *AnytimeDStar) path() Path {
+	g := d.g
+	path := Path{Points: []Point{g.point(d.start)}}
+	for v, steps := d.start, 0; v != d.goal && steps < len(d.cost); steps++ {
+		next, step := -1, math.Inf(1)
+		best := math.Inf(1)
+		for _, e := range d.adj[v] {
+			c, _ := g.stepCost(d.o, v, e)
+			if c+d.cost[e.to] < best {
+				next, step, best = e.to, c, c+d.cost[e.to]
+			}
+		}
+		if next < 0 {
+			break
+		}
+		path.Points = append(path.Points, g.point(next))
+		path.Cost += step
+		v = next
+	}
+	return path
+}
@@ -0,0 +1,117 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestAnytimeDStar(t *testing.T) {
+	const grid = "S.2.#...\n" +
+		".#3.#.#.\n" +
+		".#..1.#.\n" +
+		".###..#.\n" +
+		"..5..9.G\n"
+	p := func(x, y int) dijkstrapf.Point { return dijkstrapf.Point{X: x, Y: y} }
+	tests := []struct {
+		name  string
+		edits []func(*dijkstrapf.Graph) error
+		err   error
+	}{
+		{name: "no edits"},
+		{
+			name: "wall across the path",
+			edits: []func(*dijkstrapf.Graph) error{
+				func(g *dijkstrapf.Graph) error { return g.SetWall(p(5, 4), true) },
+			},
+		},
+		{
+			name: "wall opened",
+			edits: []func(*dijkstrapf.Graph) error{
+				func(g *dijkstrapf.Graph) error { return g.SetWall(p(6, 2), false) },
+			},
+		},
+		{
+			name: "weights changed",
+			edits: []func(*dijkstrapf.Graph) error{
+				func(g *dijkstrapf.Graph) error { return g.SetWeight(p(2, 0), 1) },
+				func(g *dijkstrapf.Graph) error { return g.SetWeight(p(4, 2), 9) },
+			},
+		},
+		{
+			name: "start moved",
+			edits: []func(*dijkstrapf.Graph) error{
+				func(g *dijkstrapf.Graph) error { return g.SetStart(p(3, 2)) },
+			},
+		},
+		{
+			name: "diagonal moves",
+			edits: []func(*dijkstrapf.Graph) error{
+				func(g *dijkstrapf.Graph) error { g.SetDiagonal(true); return nil },
+			},
+		},
+		{
+			name: "goal walled off",
+			edits: []func(*dijkstrapf.Graph) error{
+				func(g *dijkstrapf.Graph) error { return g.SetWall(p(7, 3), true) },
+				func(g *dijkstrapf.Graph) error { return g.SetWall(p(6, 4), true) },
+			},
+			err: dijkstrapf.ErrNoPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := dijkstrapf.NewAnytimeDStar(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer d.Close()
+			check := func(when string, wantErr error) {
+				t.Helper()
+				path, bound, err := d.Plan(time.Time{})
+				if wantErr != nil {
+					if !errors.Is(err, wantErr) {
+						t.Fatalf("%s: err = %v, want %v", when, err, wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", when, err)
+				}
+				if bound != 1 {
+					t.Fatalf("%s: bound %g without a deadline, want 1", when, bound)
+				}
+				want, err := g.FindPath()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if path.Cost != want.Cost {
+					t.Fatalf("%s: cost %g, FindPath gives %g", when, path.Cost, want.Cost)
+				}
+				if err := g.ValidatePath(path); err != nil {
+					t.Fatalf("%s: %v", when, err)
+				}
+			}
+			check("first plan", nil)
+			if d.Expanded() == 0 {
+				t.Fatal("first plan expanded nothing")
+			}
+			if len(tt.edits) == 0 {
+				return
+			}
+			for _, edit := range tt.edits {
+				if err := edit(g); err != nil {
+					t.Fatal(err)
+				}
+			}
+			check("after edits", tt.err)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoEdge is returned by RemoveEdge when there is no edge to remove.
+var ErrNoEdge = errors.New("dijkstrapf: no such edge")
+
+// Edge is a move out of a cell and the cost of taking it.
+type Edge struct {
+	To   Point
+	Cost float64
+}
+
+// Edges returns the moves out of p: its grid neighbours, minus removed
+// edges, plus the edges added with AddEdge.
+func (g *Graph) Edges(p Point) []Edge {
+	if !g.InBounds(p) {
+		return nil
+	}
+	var out []Edge
+	for _, e := range g.adjacency()[g.id(p)] {
+		out = append(out, Edge{g.point(e.to), e.cost})
+	}
+	return out
+}
+
+// AddEdge adds a one-way edge from a to b that costs cost to take, on top
+// of the grid neighbourhood, such as a bridge, ladder or teleporter. Add a
+// second edge from b to a for a two-way link. The edge only takes effect
+// while both cells are walkable.
+func (g *Graph) AddEdge(a, b Point, cost float64) error {
+	if !g.InBounds(a) || !g.InBounds(b) {
+		return ErrOutOfBounds
+	}
+	if !(cost >= 0) {
+		return ErrNegativeCost
+	}
+	g.addExtraEdge(a, b, cost)
+	return nil
+}
+
+// RemoveEdge removes the edges from a to b added with AddEdge or, if there
+// are none, the grid move from a to b, so that cutting a single step does
+// not take a wall. Only that direction is removed. Removed grid moves stay
+// removed until the edge is added back with AddEdge.
+func (g *Graph) RemoveEdge(a, b Point) error {
+	if !g.InBounds(a) || !g.InBounds(b) {
+		return ErrOutOfBounds
+	}
+	from, to := g.id(a), g.id(b)
+	kept := g.extra[from][:0]
+	for _, e := range g.extra[from] {
+		if e.to != to {
+			kept = append(kept, e)
+		}
+	}
+	switch {
+	case len(kept) < len(g.extra[from]):
+		if len(kept) == 0 {
+			delete(g.extra, from)
+		} else {
+			g.extra[from] = kept
+		}
+	case g.isNeighbour(a, b) && !g.removed[[2]int{from, to}]:
+		if g.removed == nil {
+			g.removed = make(map[[2]int]bool)
+		}
+		g.removed[[2]int{from, to}] = true
+	default:
+		return fmt.Errorf("%w from %v to %v", ErrNoEdge, a, b)
+	}
+	g.stale = true
+	g.changed(ChangeLayout, Point{-1, -1})
+	return nil
+}
+
+// hasShortcut reports whether an added edge costs less than the grid
+// distance it spans at weight w.
+func (g *Graph) hasShortcut(w float64) bool {
+	for from, edges := range g.extra {
+		for _, e := range edges {
+			dx, dy := g.delta(g.point(from), g.point(e.to))
+			if e.cost < w*g.gridDistance(dx, dy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNeighbour reports whether the grid neighbourhood has a move from a to
+// b, walls aside.
+func (g *Graph) isNeighbour(a, b Point) bool {
+	dx, dy := g.delta(a, b)
+	if dx+dy == 0 || dx > 1 || dy > 1 {
+		return false
+	}
+	return dx+dy == 1 || g.diagonal
+}
@@ -0,0 +1,117 @@
+package dijkstrapf
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// ErrOutOfEnergy is returned by FindPathEnergy when the goal can be
+// reached, but not without running the battery flat. It wraps ErrNoPath.
+var ErrOutOfEnergy = fmt.Errorf("%w: battery runs flat", ErrNoPath)
+
+// Battery describes the energy store of a drone or rover for
+// FindPathEnergy.
+type Battery struct {
+	// Capacity is the most energy the battery holds.
+	Capacity float64
+	// Charge is the energy held at the start; zero means a full battery.
+	Charge float64
+	// Drain returns the energy a step uses; nil uses the cost of the step.
+	Drain CostFunc
+	// Chargers are cells that fill the battery up when entered.
+	Chargers []Point
+}
+
+// FindPathEnergy finds the cheapest path from the start to the goal that
+// never needs more energy than the battery holds: every step drains the
+// battery, and entering a charger fills it up again. It may take detours
+// to chargers, and back and forth through them, that a plain shortest path
+// would not. The search keeps, for every cell, each way of reaching it
+// that no other beats on both cost and energy left, as FindPareto does.
+func (g *Graph) FindPathEnergy(b Battery, opts ...Option) (Path, error) {
+	return g.run("energy", func(g *Graph, o *Options) (Path, error) {
+		if o.Reverse {
+			return Path{}, errForwardOnly
+		}
+		src, dst, err := g.endpoints()
+		if err != nil {
+			return Path{}, err
+		}
+		if !(b.Capacity >= 0) || b.Charge < 0 || b.Charge > b.Capacity {
+			return Path{}, fmt.Errorf("%w: battery charge %g of capacity %g", ErrBadWeight, b.Charge, b.Capacity)
+		}
+		charger := make(map[int]bool, len(b.Chargers))
+		for _, p := range b.Chargers {
+			if g.InBounds(p) {
+				charger[g.id(p)] = true
+			}
+		}
+		adj := g.adjacency()
+
+		// A label's second cost is the energy used since the battery was
+		// last full, so fewer is better on both counts.
+		used := 0.0
+		if b.Charge > 0 {
+			used = b.Capacity - b.Charge
+		}
+		var labels []label
+		settled := make([][]int, g.nodeCount())
+		pq := &labelQueue{labels: &labels}
+		labels = append(labels, label{node: src, parent: -1, costs: [2]float64{0, used}})
+		heap.Push(pq, 0)
+		flat, goal := false, -1
+		for pq.Len() > 0 {
+			li := heap.Pop(pq).(int)
+			l := labels[li]
+			if dominated(labels, settled[l.node], l.costs) {
+				continue
+			}
+			settled[l.node] = append(settled[l.node], li)
+			g.settle(o, l.node, l.costs[0])
+			if l.node == dst {
+				goal = li
+				break
+			}
+			if err := g.cancelled(o); err != nil {
+				return Path{}, err
+			}
+			for _, e := range adj[l.node] {
+				c, err := g.stepCost(o, l.node, e)
+				if err != nil {
+					return Path{}, err
+				}
+				drain := c
+				if b.Drain != nil {
+					drain = b.Drain(g.point(l.node), g.point(e.to))
+					if drain < 0 || math.IsNaN(drain) {
+						return Path{}, ErrNegativeCost
+					}
+				}
+				costs := [2]float64{l.costs[0] + c, l.costs[1] + drain}
+				if costs[1] > b.Capacity {
+					flat = true
+					continue
+				}
+				if charger[e.to] {
+					costs[1] = 0
+				}
+				if math.IsInf(costs[0], 1) || dominated(labels, settled[e.to], costs) {
+					continue
+				}
+				labels = append(labels, label{node: e.to, parent: li, costs: costs})
+				heap.Push(pq, len(labels)-1)
+			}
+		}
+
+		if goal < 0 {
+			g.finish(o, dst, math.Inf(1))
+			if flat {
+				return Path{}, ErrOutOfEnergy
+			}
+			return Path{}, ErrNoPath
+		}
+		g.finish(o, dst, labels[goal].costs[0])
+		return Path{Points: labelPoints(g, labels, goal), Cost: labels[goal].costs[0]}, nil
+	}, opts)
+}
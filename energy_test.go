@@ -0,0 +1,109 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestFindPathEnergy(t *testing.T) {
+	const grid = "S.....G\n" +
+		"###.###\n" +
+		".......\n"
+	charger := []dijkstrapf.Point{{X: 3, Y: 2}}
+	double := func(_, _ dijkstrapf.Point) float64 { return 2 }
+	tests := []struct {
+		name    string
+		grid    string
+		battery dijkstrapf.Battery
+		cost    float64
+		detour  bool
+		err     error
+	}{
+		{name: "big battery", grid: grid, battery: dijkstrapf.Battery{Capacity: 100}, cost: 6},
+		{name: "just enough", grid: grid, battery: dijkstrapf.Battery{Capacity: 6}, cost: 6},
+		{
+			name:    "detour to charger",
+			grid:    grid,
+			battery: dijkstrapf.Battery{Capacity: 5, Chargers: charger},
+			cost:    10,
+			detour:  true,
+		},
+		{
+			name:    "half charged",
+			grid:    grid,
+			battery: dijkstrapf.Battery{Capacity: 10, Charge: 5, Chargers: charger},
+			cost:    10,
+			detour:  true,
+		},
+		{
+			name:    "charger on the way",
+			grid:    grid,
+			battery: dijkstrapf.Battery{Capacity: 3, Chargers: []dijkstrapf.Point{{X: 3}}},
+			cost:    6,
+		},
+		{
+			name:    "custom drain",
+			grid:    grid,
+			battery: dijkstrapf.Battery{Capacity: 12, Drain: double},
+			cost:    6,
+		},
+		{
+			name:    "no charger",
+			grid:    grid,
+			battery: dijkstrapf.Battery{Capacity: 5},
+			err:     dijkstrapf.ErrOutOfEnergy,
+		},
+		{
+			name:    "walled off",
+			grid:    "S..#..G\n",
+			battery: dijkstrapf.Battery{Capacity: 100},
+			err:     dijkstrapf.ErrNoPath,
+		},
+		{
+			name:    "overcharged",
+			grid:    grid,
+			battery: dijkstrapf.Battery{Capacity: 5, Charge: 6},
+			err:     dijkstrapf.ErrBadWeight,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tt.grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			path, err := g.FindPathEnergy(tt.battery)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path.Cost != tt.cost {
+				t.Fatalf("cost %g, want %g", path.Cost, tt.cost)
+			}
+			if err := g.ValidatePath(path); err != nil {
+				t.Fatal(err)
+			}
+			want, err := g.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.detour && path.Cost <= want.Cost || !tt.detour && path.Cost != want.Cost {
+				t.Fatalf("cost %g, FindPath gives %g", path.Cost, want.Cost)
+			}
+		})
+	}
+}
+
+func TestOutOfEnergyIsNoPath(t *testing.T) {
+	if !errors.Is(dijkstrapf.ErrOutOfEnergy, dijkstrapf.ErrNoPath) {
+		t.Fatal("ErrOutOfEnergy does not wrap ErrNoPath")
+	}
+}
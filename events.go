@@ -0,0 +1,52 @@
+package dijkstrapf
+
+import (
+	"io"
+	"math"
+	"strconv"
+)
+
+// WithEvents makes the solver write every step it takes to w as a JSON
+// object on a line of its own, for visualisers outside Go. The events are
+//
+//	{"event":"settle","x":2,"y":3,"dist":5}
+//	{"event":"relax","x":2,"y":4,"old":7,"new":6}
+//	{"event":"done","x":4,"y":3,"cost":9}
+//
+// A settle event is written when a node is taken off the frontier and
+// expanded; a node is settled again when A* with an inconsistent heuristic
+// reopens it. Relax events follow their settle event, one for each
+// neighbour whose tentative distance improved. The done event ends the
+// stream with the cost of reaching the goal. Infinite distances and costs
+// are written as null. Fields may be added in the future; consumers should
+// ignore the ones they do not know.
+func WithEvents(w io.Writer) Option {
+	return func(o *Options) { o.Events = w }
+}
+
+// writeEvent writes one event line. values holds alternating field names
+// and numbers.
+func writeEvent(w io.Writer, event string, p Point, values ...any) {
+	b := make([]byte, 0, 64)
+	b = append(b, `{"event":"`...)
+	b = append(b, event...)
+	b = append(b, `","x":`...)
+	b = strconv.AppendInt(b, int64(p.X), 10)
+	b = append(b, `,"y":`...)
+	b = strconv.AppendInt(b, int64(p.Y), 10)
+	for i := 0; i+1 < len(values); i += 2 {
+		b = append(b, `,"`...)
+		b = append(b, values[i].(string)...)
+		b = append(b, `":`...)
+		b = appendJSONNumber(b, values[i+1].(float64))
+	}
+	b = append(b, "}\n"...)
+	w.Write(b)
+}
+
+func appendJSONNumber(b []byte, v float64) []byte {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return append(b, "null"...)
+	}
+	return strconv.AppendFloat(b, v, 'g', -1, 64)
+}
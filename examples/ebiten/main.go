@@ -0,0 +1,158 @@
+// Command ebiten draws a grid with the Ebiten game engine and animates the
+// search over it, using WithSettleFunc to record the order in which the
+// solver settles cells.
+//
+// Left-click paints walls, right-click erases them, S and G move the start
+// and goal to the cell under the cursor, Tab switches algorithm and Space
+// replays the animation. Run it from this directory with
+//
+//	go mod tidy
+//	go run .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+var (
+	colorEmpty   = color.RGBA{0xee, 0xee, 0xee, 0xff}
+	colorWall    = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	colorSettled = color.RGBA{0x9e, 0xc5, 0xe8, 0xff}
+	colorPath    = color.RGBA{0xf2, 0xc1, 0x4e, 0xff}
+	colorStart   = color.RGBA{0x3c, 0xb3, 0x71, 0xff}
+	colorGoal    = color.RGBA{0xd9, 0x4f, 0x4f, 0xff}
+)
+
+type game struct {
+	g     *dijkstrapf.Graph
+	cell  int
+	speed int
+	algos []string
+	algo  int
+
+	// order holds the settled cells of the last solve in the order they
+	// were settled; shown is how many of them are drawn so far.
+	order []dijkstrapf.Point
+	shown int
+	path  dijkstrapf.Path
+	err   error
+}
+
+// solve runs the current algorithm and restarts the animation.
+func (s *game) solve() {
+	s.order = s.order[:0]
+	s.shown = 0
+	s.path, s.err = s.g.Solve(s.algos[s.algo], dijkstrapf.WithSettleFunc(func(p dijkstrapf.Point, _ float64) {
+		s.order = append(s.order, p)
+	}))
+}
+
+func (s *game) cursor() (dijkstrapf.Point, bool) {
+	x, y := ebiten.CursorPosition()
+	p := dijkstrapf.Point{X: x / s.cell, Y: y / s.cell}
+	return p, s.g.InBounds(p)
+}
+
+func (s *game) Update() error {
+	edited := false
+	if p, ok := s.cursor(); ok {
+		marker := s.g.Cell(p) == dijkstrapf.Start || s.g.Cell(p) == dijkstrapf.Goal
+		switch {
+		case ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && !marker && !s.g.IsWall(p):
+			edited = s.g.SetWall(p, true) == nil
+		case ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) && s.g.IsWall(p):
+			edited = s.g.SetWall(p, false) == nil
+		case inpututil.IsKeyJustPressed(ebiten.KeyS):
+			edited = s.g.SetStart(p) == nil
+		case inpututil.IsKeyJustPressed(ebiten.KeyG):
+			edited = s.g.SetGoal(p) == nil
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		s.algo = (s.algo + 1) % len(s.algos)
+		edited = true
+	}
+	if edited || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		s.solve()
+	}
+	s.shown = min(s.shown+s.speed, len(s.order))
+	return nil
+}
+
+func (s *game) fill(screen *ebiten.Image, p dijkstrapf.Point, c color.Color) {
+	size := float32(s.cell)
+	vector.DrawFilledRect(screen, float32(p.X)*size, float32(p.Y)*size, size-1, size-1, c, false)
+}
+
+func (s *game) Draw(screen *ebiten.Image) {
+	for y := 0; y < s.g.Height(); y++ {
+		for x := 0; x < s.g.Width(); x++ {
+			p := dijkstrapf.Point{X: x, Y: y}
+			c := colorEmpty
+			if s.g.IsWall(p) {
+				c = colorWall
+			}
+			s.fill(screen, p, c)
+		}
+	}
+	for _, p := range s.order[:s.shown] {
+		s.fill(screen, p, colorSettled)
+	}
+	if s.shown == len(s.order) {
+		for _, p := range s.path.Points {
+			s.fill(screen, p, colorPath)
+		}
+	}
+	if p, ok := s.g.Start(); ok {
+		s.fill(screen, p, colorStart)
+	}
+	if p, ok := s.g.Goal(); ok {
+		s.fill(screen, p, colorGoal)
+	}
+
+	status := fmt.Sprintf("%s: %d settled", s.algos[s.algo], s.shown)
+	switch {
+	case s.err != nil:
+		status += ", " + s.err.Error()
+	case s.shown == len(s.order):
+		status += fmt.Sprintf(", cost %g", s.path.Cost)
+	}
+	ebitenutil.DebugPrint(screen, status)
+}
+
+func (s *game) Layout(int, int) (int, int) {
+	return s.g.Width() * s.cell, s.g.Height() * s.cell
+}
+
+func main() {
+	width := flag.Int("width", 63, "width of the maze in cells")
+	height := flag.Int("height", 41, "height of the maze in cells")
+	cell := flag.Int("cell", 16, "size of a cell in `pixels`")
+	speed := flag.Int("speed", 4, "cells revealed per frame")
+	seed := flag.Int64("seed", 1, "seed for the maze")
+	flag.Parse()
+
+	s := &game{
+		g:     dijkstrapf.GenerateMaze(*width, *height, *seed),
+		cell:  *cell,
+		speed: max(1, *speed),
+		algos: []string{"dijkstra", "astar", "bfs", "jps"},
+	}
+	s.solve()
+
+	ebiten.SetWindowSize(*width**cell, *height**cell)
+	ebiten.SetWindowTitle("dijkstrapf")
+	if err := ebiten.RunGame(s); err != nil {
+		log.Fatal(err)
+	}
+}
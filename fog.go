@@ -0,0 +1,149 @@
+package dijkstrapf
+
+import "time"
+
+// FogOfWar walks an agent from the start of a grid to its goal when the
+// agent only knows the cells within its sensing radius. It keeps the map
+// the agent has learnt as a graph of its own, on which every cell it has
+// not seen yet is assumed to be empty ground of weight 1. The agent plans
+// on that map, takes a step, senses the cells around it and plans again,
+// repairing the plan with Anytime D* wherever the new cells differ from
+// what it assumed.
+//
+// The true grid must not be edited while the walk is under way. Its
+// diagonal, corner, diagonal cost and wrap settings are copied to the known
+// map; edges added with AddEdge are not, since the agent cannot see them.
+type FogOfWar struct {
+	truth, known *Graph
+	radius       int
+	o            *Options
+	planner      *AnytimeDStar
+	seen         []bool
+	walked       Path
+}
+
+// NewFogOfWar places an agent on the start of truth that senses the cells
+// within radius of it, as the crow flies, and plans its way to the goal
+// with opts.
+func NewFogOfWar(truth *Graph, radius int, opts ...Option) (*FogOfWar, error) {
+	if _, _, err := truth.endpoints(); err != nil {
+		return nil, err
+	}
+	known := NewGraph(truth.width, truth.height)
+	known.diagonal = truth.diagonal
+	known.diagonalCost = truth.diagonalCost
+	known.corners = truth.corners
+	known.wrap = truth.wrap
+	known.SetStart(truth.start)
+	known.SetGoal(truth.goal)
+	f := &FogOfWar{
+		truth: truth, known: known, radius: max(radius, 0),
+		o: buildOptions(opts), seen: make([]bool, truth.nodeCount()),
+		walked: Path{Points: []Point{truth.start}},
+	}
+	f.sense()
+	planner, err := NewAnytimeDStar(known, append(opts[:len(opts):len(opts)], WithEpsilon(1))...)
+	if err != nil {
+		return nil, err
+	}
+	f.planner = planner
+	return f, nil
+}
+
+// Known returns the map as the agent knows it, for drawing. It must not be
+// edited.
+func (f *FogOfWar) Known() *Graph { return f.known }
+
+// Seen reports whether the agent has sensed p.
+func (f *FogOfWar) Seen(p Point) bool {
+	return f.truth.InBounds(p) && f.seen[f.truth.id(p)]
+}
+
+// Pos returns the cell the agent stands on.
+func (f *FogOfWar) Pos() Point { return f.known.start }
+
+// Done reports whether the agent has reached the goal.
+func (f *FogOfWar) Done() bool { return f.known.start == f.known.goal }
+
+// Walked returns the cells the agent has walked so far, with their cost on
+// the true grid.
+func (f *FogOfWar) Walked() Path { return f.walked }
+
+// Plan returns the path the agent currently means to take: the shortest
+// one on the map it knows. It fails with ErrNoPath once what the agent has
+// seen rules out every way to the goal.
+func (f *FogOfWar) Plan() (Path, error) {
+	path, _, err := f.planner.Plan(time.Time{})
+	return path, err
+}
+
+// Step moves the agent one cell along its plan and senses around the cell
+// it arrives on. With a radius of 0 the agent only learns of a wall by
+// bumping into it, which costs the step.
+func (f *FogOfWar) Step() error {
+	if f.Done() {
+		return nil
+	}
+	path, err := f.Plan()
+	if err != nil {
+		return err
+	}
+	from, to := path.Points[0], path.Points[1]
+	if f.truth.IsWall(to) {
+		f.seen[f.truth.id(to)] = true
+		return f.known.SetWall(to, true)
+	}
+	c, err := f.truth.stepCost(f.o, f.truth.id(from), edge{f.truth.id(to), f.truth.edgeCost(from, to)})
+	if err != nil {
+		return err
+	}
+	f.walked.Points = append(f.walked.Points, to)
+	f.walked.Cost += c
+	if err := f.known.SetStart(to); err != nil {
+		return err
+	}
+	f.sense()
+	return nil
+}
+
+// Run steps the agent until it reaches the goal, for at most maxSteps
+// steps if maxSteps is positive, and returns the cells it walked.
+func (f *FogOfWar) Run(maxSteps int) (Path, error) {
+	for n := 0; !f.Done() && (maxSteps <= 0 || n < maxSteps); n++ {
+		if err := f.Step(); err != nil {
+			return f.walked, err
+		}
+	}
+	return f.walked, nil
+}
+
+// sense copies the true state of the cells within the radius to the known
+// map.
+func (f *FogOfWar) sense() {
+	t, at := f.truth, f.known.start
+	for dy := -f.radius; dy <= f.radius; dy++ {
+		for dx := -f.radius; dx <= f.radius; dx++ {
+			if dx*dx+dy*dy > f.radius*f.radius {
+				continue
+			}
+			q := Point{at.X + dx, at.Y + dy}
+			if t.wrap {
+				q = Point{(q.X%t.width + t.width) % t.width, (q.Y%t.height + t.height) % t.height}
+			}
+			if !t.InBounds(q) || f.seen[t.id(q)] {
+				continue
+			}
+			f.seen[t.id(q)] = true
+			if t.IsWall(q) {
+				f.known.SetWall(q, true)
+				continue
+			}
+			if w := t.Weight(q); w != f.known.Weight(q) {
+				f.known.SetWeight(q, w)
+			}
+			if d, ok := t.doors[t.id(q)]; ok {
+				f.known.SetDoor(q, d.state, d.cost)
+			}
+		}
+	}
+}
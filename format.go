@@ -0,0 +1,128 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrBadMap is wrapped by every error LoadGrid returns for malformed input.
+var ErrBadMap = errors.New("dijkstrapf: malformed map")
+
+// Map file symbols. Digits 1-9 mark walkable cells with that weight.
+const (
+	SymbolEmpty = '.'
+	SymbolWall  = '#'
+	SymbolStart = 'S'
+	SymbolGoal  = 'G'
+)
+
+// maxRowLength bounds the width of a map LoadGrid accepts.
+const maxRowLength = 1 << 20
+
+// LoadGrid reads a text map: one line per row, using '.' for empty cells,
+// '#' for walls, 'S' and 'G' for the start and goal and the digits 1-9 for
+// weighted cells. The symbols of DefaultTerrains mark cells of that
+// terrain. Lines starting with ';' are comments. All rows must have the
+// same length.
+func LoadGrid(r io.Reader) (*Graph, error) {
+	var rows []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxRowLength)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.HasPrefix(line, ";") {
+			continue
+		}
+		rows = append(rows, line)
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: row longer than %d cells", ErrBadMap, maxRowLength)
+		}
+		return nil, err
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: empty map", ErrBadMap)
+	}
+
+	width := len(rows[0])
+	if width == 0 {
+		return nil, fmt.Errorf("%w: empty first row", ErrBadMap)
+	}
+	g := NewGraph(width, len(rows))
+	for y, row := range rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("%w: row %d has length %d, want %d", ErrBadMap, y+1, len(row), width)
+		}
+		for x := 0; x < width; x++ {
+			p := Point{x, y}
+			switch c := row[x]; {
+			case c == SymbolEmpty:
+			case c == SymbolWall:
+				g.SetWall(p, true)
+			case c == SymbolStart:
+				if g.hasStart {
+					return nil, fmt.Errorf("%w: second start at %v", ErrBadMap, p)
+				}
+				g.SetStart(p)
+			case c == SymbolGoal:
+				if g.hasGoal {
+					return nil, fmt.Errorf("%w: second goal at %v", ErrBadMap, p)
+				}
+				g.SetGoal(p)
+			case c >= '1' && c <= '9':
+				g.SetWeight(p, float64(c-'0'))
+			case g.terrainBySymbol(c) >= 0:
+				g.SetTerrain(p, g.terrains[g.terrainBySymbol(c)].Name)
+			default:
+				return nil, fmt.Errorf("%w: unknown symbol %q at %v", ErrBadMap, c, p)
+			}
+		}
+	}
+	return g, nil
+}
+
+// WriteGrid writes g in the text format read by LoadGrid. Cell weights must
+// be whole numbers from 1 to 9 to be representable.
+func (g *Graph) WriteGrid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			c, err := g.symbol(Point{x, y})
+			if err != nil {
+				return err
+			}
+			bw.WriteByte(c)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func (g *Graph) symbol(p Point) (byte, error) {
+	switch g.gridMatrix[p.Y][p.X] {
+	case Wall:
+		return SymbolWall, nil
+	case Start:
+		return SymbolStart, nil
+	case Goal:
+		return SymbolGoal, nil
+	}
+	w := g.weights[p.Y][p.X]
+	if i := g.terrainOf(p); i >= 0 && g.terrains[i].Cost == w {
+		return g.terrains[i].Symbol, nil
+	}
+	switch {
+	case w == 1:
+		return SymbolEmpty, nil
+	case w == float64(int(w)) && w >= 2 && w <= 9:
+		return byte('0' + int(w)), nil
+	}
+	return 0, fmt.Errorf("dijkstrapf: weight %g at %v has no map symbol", w, p)
+}
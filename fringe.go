@@ -0,0 +1,103 @@
+package dijkstrapf
+
+import "math"
+
+// FindPathFringe runs fringe search. Like IDA* it works in rounds with a
+// growing f-limit, but it keeps the frontier between rounds in a plain
+// linked list instead of repeating the search from the start, and needs no
+// priority queue. It uses the same heuristic as A*.
+func (g *Graph) FindPathFringe(opts ...Option) (Path, error) {
+	return g.run("fringe", fringe, opts)
+}
+
+func fringe(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+	h := g.heuristic(o)
+	eps := o.epsilon()
+	f := func(n int, d float64) float64 { return d + eps*h(g.point(n), g.goal) }
+
+	// The fringe is a doubly linked list over node ids, with end as the
+	// sentinel at both ends.
+	n := g.nodeCount()
+	end := n
+	next := make([]int, n+1)
+	back := make([]int, n+1)
+	listed := make([]bool, n)
+	next[end], back[end] = end, end
+	insertAfter := func(at, node int) {
+		next[node], back[node] = next[at], at
+		back[next[at]] = node
+		next[at] = node
+		listed[node] = true
+	}
+	remove := func(node int) {
+		next[back[node]] = next[node]
+		back[next[node]] = back[node]
+		listed[node] = false
+	}
+
+	dist, prev := g.resetSearch()
+	dist[src] = 0
+	insertAfter(end, src)
+	limit := f(src, 0)
+	found := false
+	for !found && next[end] != end {
+		least := math.Inf(1)
+		for cur := next[end]; cur != end; {
+			if fc := f(cur, dist[cur]); fc > limit {
+				least = min(least, fc)
+				cur = next[cur]
+				continue
+			}
+			g.settle(o, cur, dist[cur])
+			if cur == dst {
+				found = true
+				break
+			}
+			if err := g.cancelled(o); err != nil {
+				return g.partial(o, dist, prev, err)
+			}
+			// Children go right after cur, so this round visits them next.
+			edges := adj[cur]
+			for i := len(edges) - 1; i >= 0; i-- {
+				e := edges[i]
+				c, err := g.stepCost(o, cur, e)
+				if err != nil {
+					return Path{}, err
+				}
+				nd := dist[cur] + c
+				if nd >= dist[e.to] {
+					continue
+				}
+				g.relax(o, e.to, dist[e.to], nd)
+				if listed[e.to] {
+					remove(e.to)
+				}
+				insertAfter(cur, e.to)
+				dist[e.to] = nd
+				prev[e.to] = cur
+			}
+			after := next[cur]
+			remove(cur)
+			cur = after
+		}
+		limit = least
+	}
+
+	g.finish(o, dst, dist[dst])
+	if !found {
+		return g.partial(o, dist, prev, ErrNoPath)
+	}
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, nil
+}
+
+func init() {
+	Register("fringe", fringe)
+}
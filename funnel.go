@@ -0,0 +1,133 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// Waypoint is a point in continuous grid coordinates: cell (x, y) covers
+// the square from (x, y) to (x+1, y+1), so its centre is (x+0.5, y+0.5).
+type Waypoint struct {
+	X, Y float64
+}
+
+// Waypoints is a polyline through the grid.
+type Waypoints []Waypoint
+
+// Length returns the Euclidean length of the polyline.
+func (w Waypoints) Length() float64 {
+	total := 0.0
+	for i := 1; i < len(w); i++ {
+		total += math.Hypot(w[i].X-w[i-1].X, w[i].Y-w[i-1].Y)
+	}
+	return total
+}
+
+// centreOf returns the centre of cell p.
+func centreOf(p Point) Waypoint {
+	return Waypoint{float64(p.X) + 0.5, float64(p.Y) + 0.5}
+}
+
+// portal is the edge two neighbouring regions share, with its ends named
+// as seen when crossing it.
+type portal struct {
+	left, right Waypoint
+}
+
+// FindPathFunnel finds the chain of navigation mesh regions leading from
+// the start to the goal, as FindPathNavMesh does, and pulls a string
+// through it with the funnel algorithm: the result is the shortest
+// polyline from the centre of the start to the centre of the goal that
+// stays inside the chain, turning only at corners of the portal edges
+// between its regions. Cell weights shape the chain but not the line
+// within it. It returns ErrUnsupported when the chain steps between
+// regions that do not touch, across a wrapping edge or an added edge.
+func (g *Graph) FindPathFunnel() (Waypoints, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return nil, err
+	}
+	m := g.NavMesh()
+	chain := g.regionChain(m, m.regionOf[src], m.regionOf[dst])
+	if chain == nil {
+		return nil, ErrNoPath
+	}
+	start, goal := centreOf(g.start), centreOf(g.goal)
+	portals := []portal{{start, start}}
+	for i := 1; i < len(chain); i++ {
+		p, ok := regionPortal(m.Regions[chain[i-1]], m.Regions[chain[i]])
+		if !ok {
+			return nil, fmt.Errorf("%w: regions %d and %d do not touch", ErrUnsupported, chain[i-1], chain[i])
+		}
+		portals = append(portals, p)
+	}
+	portals = append(portals, portal{goal, goal})
+	return pullString(portals), nil
+}
+
+// regionPortal returns the edge or corner a and b share, with its ends
+// ordered as seen when moving from a to b.
+func regionPortal(a, b Region) (portal, bool) {
+	x0, x1 := max(a.X, b.X), min(a.X+a.Width, b.X+b.Width)
+	y0, y1 := max(a.Y, b.Y), min(a.Y+a.Height, b.Y+b.Height)
+	if x0 > x1 || y0 > y1 {
+		return portal{}, false
+	}
+	p := portal{Waypoint{float64(x0), float64(y0)}, Waypoint{float64(x1), float64(y1)}}
+	ca, cb := regionCentre(a), regionCentre(b)
+	if cross(ca, cb, p.left) > cross(ca, cb, p.right) {
+		p.left, p.right = p.right, p.left
+	}
+	return p, true
+}
+
+func regionCentre(r Region) Waypoint {
+	return Waypoint{float64(r.X) + float64(r.Width)/2, float64(r.Y) + float64(r.Height)/2}
+}
+
+// cross returns the z component of (b-a) x (c-a): negative when c lies to
+// the left of the line from a to b, with y growing downwards.
+func cross(a, b, c Waypoint) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// pullString runs the simple stupid funnel algorithm over portals, the
+// first and last of which are the endpoints.
+func pullString(portals []portal) Waypoints {
+	apex := portals[0].left
+	left, right := apex, apex
+	apexAt, leftAt, rightAt := 0, 0, 0
+	path := Waypoints{apex}
+	for i := 1; i < len(portals); i++ {
+		l, r := portals[i].left, portals[i].right
+		// Tighten the right side of the funnel, or restart it from the
+		// left corner if the right side would cross over it.
+		if cross(apex, right, r) <= 0 {
+			if apex == right || cross(apex, left, r) > 0 {
+				right, rightAt = r, i
+			} else {
+				apex, apexAt = left, leftAt
+				path = append(path, apex)
+				left, right, leftAt, rightAt = apex, apex, apexAt, apexAt
+				i = apexAt
+				continue
+			}
+		}
+		// Likewise for the left side.
+		if cross(apex, left, l) >= 0 {
+			if apex == left || cross(apex, right, l) < 0 {
+				left, leftAt = l, i
+			} else {
+				apex, apexAt = right, rightAt
+				path = append(path, apex)
+				left, right, leftAt, rightAt = apex, apex, apexAt, apexAt
+				i = apexAt
+				continue
+			}
+		}
+	}
+	if goal := portals[len(portals)-1].left; path[len(path)-1] != goal {
+		path = append(path, goal)
+	}
+	return path
+}
@@ -0,0 +1,126 @@
+package dijkstrapf_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+var fuzzSeeds = []string{
+	"S..\n.#.\n..G\n",
+	"S#G\n",
+	"S.5\n#9.\n.2G\n",
+	"; comment\nSG\n",
+	"S.\n.\n",
+	"SS\nGG\n",
+	"\n\n",
+	"S.x\n..G\n",
+}
+
+// FuzzLoadGrid checks that LoadGrid never panics and that every map it
+// accepts survives a WriteGrid round trip unchanged.
+func FuzzLoadGrid(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := dijkstrapf.LoadGrid(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		var first bytes.Buffer
+		if err := g.WriteGrid(&first); err != nil {
+			t.Fatalf("WriteGrid: %v", err)
+		}
+		g2, err := dijkstrapf.LoadGrid(bytes.NewReader(first.Bytes()))
+		if err != nil {
+			t.Fatalf("reloading %q: %v", first.String(), err)
+		}
+		var second bytes.Buffer
+		if err := g2.WriteGrid(&second); err != nil {
+			t.Fatalf("WriteGrid: %v", err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Fatalf("round trip changed map:\n%s\nto\n%s", first.String(), second.String())
+		}
+	})
+}
+
+// FuzzLoadBinary checks that LoadBinary never panics and that every graph
+// it accepts writes back to the same bytes.
+func FuzzLoadBinary(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		g, err := dijkstrapf.LoadGrid(bytes.NewReader([]byte(s)))
+		if err != nil {
+			continue
+		}
+		g.SetDiagonal(true)
+		g.AddEdge(dijkstrapf.Point{X: 0, Y: 0}, dijkstrapf.Point{X: 1, Y: 0}, 0.5)
+		var buf bytes.Buffer
+		if err := g.WriteBinary(&buf); err != nil {
+			f.Fatalf("WriteBinary: %v", err)
+		}
+		f.Add(buf.Bytes())
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := dijkstrapf.LoadBinary(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		var first bytes.Buffer
+		if err := g.WriteBinary(&first); err != nil {
+			t.Fatalf("WriteBinary: %v", err)
+		}
+		g2, err := dijkstrapf.LoadBinary(bytes.NewReader(first.Bytes()))
+		if err != nil {
+			t.Fatalf("reloading: %v", err)
+		}
+		var second bytes.Buffer
+		if err := g2.WriteBinary(&second); err != nil {
+			t.Fatalf("WriteBinary: %v", err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Fatalf("round trip changed graph:\n%x\nto\n%x", first.Bytes(), second.Bytes())
+		}
+	})
+}
+
+// FuzzSolve runs every registered solver on arbitrary maps. Solvers must
+// not panic, every path they return must validate, and the optimal solvers
+// must agree on the cost.
+func FuzzSolve(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s), false)
+		f.Add([]byte(s), true)
+	}
+	optimal := map[string]bool{"dijkstra": true, "astar": true, "jps": true, "fringe": true}
+	f.Fuzz(func(t *testing.T, data []byte, diagonal bool) {
+		g, err := dijkstrapf.LoadGrid(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		g.SetDiagonal(diagonal)
+		want, wantErr := g.FindPath()
+		for _, algo := range dijkstrapf.Algorithms() {
+			p, err := g.Solve(algo)
+			if errors.Is(err, dijkstrapf.ErrUnsupported) {
+				continue
+			}
+			if (err == nil) != (wantErr == nil) {
+				t.Fatalf("%s: error %v, dijkstra error %v", algo, err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if err := g.ValidatePath(p); err != nil {
+				t.Fatalf("%s: %v", algo, err)
+			}
+			if optimal[algo] && math.Abs(p.Cost-want.Cost) > 1e-9 {
+				t.Fatalf("%s: cost %g, dijkstra cost %g", algo, p.Cost, want.Cost)
+			}
+		}
+	})
+}
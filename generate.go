@@ -0,0 +1,132 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// GenerateOpen returns a width x height grid with no walls, the start in the
+// top-left and the goal in the bottom-right corner.
+func GenerateOpen(width, height int) *Graph {
+	g := NewGraph(width, height)
+	g.SetStart(Point{0, 0})
+	g.SetGoal(Point{width - 1, height - 1})
+	return g
+}
+
+// GenerateRandom returns a grid where each cell is a wall with probability
+// density, with the start and goal in opposite corners. The same seed always
+// produces the same grid; reachability of the goal is not guaranteed.
+func GenerateRandom(width, height int, density float64, seed int64) *Graph {
+	return GenerateRandomFrom(width, height, density, rand.New(rand.NewSource(seed)))
+}
+
+// GenerateRandomFrom is GenerateRandom drawing from r instead of a fresh
+// source.
+func GenerateRandomFrom(width, height int, density float64, r *rand.Rand) *Graph {
+	g := NewGraph(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if r.Float64() < density {
+				g.gridMatrix[y][x] = Wall
+			}
+		}
+	}
+	g.SetStart(Point{0, 0})
+	g.SetGoal(Point{width - 1, height - 1})
+	return g
+}
+
+// GenerateMaze returns a perfect maze carved by a randomized depth-first
+// search. Corridors run along even coordinates, so the start is (0,0) and
+// the goal is the bottom-right-most even cell.
+func GenerateMaze(width, height int, seed int64) *Graph {
+	return GenerateMazeFrom(width, height, rand.New(rand.NewSource(seed)))
+}
+
+// GenerateMazeFrom is GenerateMaze drawing from r instead of a fresh source.
+func GenerateMazeFrom(width, height int, r *rand.Rand) *Graph {
+	g := NewGraph(width, height)
+	for y := range g.gridMatrix {
+		for x := range g.gridMatrix[y] {
+			g.gridMatrix[y][x] = Wall
+		}
+	}
+	cw, ch := (width+1)/2, (height+1)/2
+	if cw == 0 || ch == 0 {
+		return g
+	}
+	visited := make([]bool, cw*ch)
+	stack := []Point{{0, 0}}
+	visited[0] = true
+	g.gridMatrix[0][0] = Empty
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		var next []Point
+		for _, d := range orthogonal {
+			n := Point{c.X + d.X, c.Y + d.Y}
+			if n.X >= 0 && n.Y >= 0 && n.X < cw && n.Y < ch && !visited[n.Y*cw+n.X] {
+				next = append(next, n)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		n := next[r.Intn(len(next))]
+		visited[n.Y*cw+n.X] = true
+		g.gridMatrix[c.Y+n.Y][c.X+n.X] = Empty
+		g.gridMatrix[2*n.Y][2*n.X] = Empty
+		stack = append(stack, n)
+	}
+	g.SetStart(Point{0, 0})
+	g.SetGoal(Point{2 * (cw - 1), 2 * (ch - 1)})
+	return g
+}
+
+// Standard map families used by the benchmarks. Each family builds a
+// size x size grid whose goal is reachable from its start, determined
+// entirely by the seed.
+var mapFamilies = map[string]func(size int, seed int64) *Graph{
+	"open": func(size int, seed int64) *Graph {
+		return GenerateOpen(size, size)
+	},
+	"maze": func(size int, seed int64) *Graph {
+		return GenerateMaze(size, size, seed)
+	},
+	"random30": func(size int, seed int64) *Graph {
+		for ; ; seed++ {
+			g := GenerateRandom(size, size, 0.3, seed)
+			if _, err := g.FindPathBFS(); err == nil {
+				return g
+			}
+		}
+	},
+}
+
+// MapFamilies returns the names of the standard map families.
+func MapFamilies() []string {
+	names := make([]string, 0, len(mapFamilies))
+	for name := range mapFamilies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenerateFamily builds the size x size member of the named map family for
+// the given seed. The benchmarks use seed 1.
+func GenerateFamily(name string, size int, seed int64) (*Graph, error) {
+	f, ok := mapFamilies[name]
+	if !ok {
+		return nil, fmt.Errorf("dijkstrapf: unknown map family %q", name)
+	}
+	if size < 2 {
+		return nil, fmt.Errorf("dijkstrapf: map size %d too small", size)
+	}
+	return f(size, seed), nil
+}
+
+// BenchSizes are the grid sizes the benchmarks run at by default.
+var BenchSizes = []int{32, 128, 512}
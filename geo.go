@@ -0,0 +1,74 @@
+package dijkstrapf
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// earthRadius is the mean Earth radius in metres.
+const earthRadius = 6371008.8
+
+// LatLon is a geographic coordinate in degrees.
+type LatLon struct {
+	Lat, Lon float64
+}
+
+// Projection maps geographic coordinates onto grid cells using a local
+// equirectangular projection, which is accurate for areas of a few tens of
+// kilometres around the origin.
+type Projection struct {
+	// Origin is the north-west corner of cell (0,0).
+	Origin LatLon
+	// CellSize is the side length of a cell in metres.
+	CellSize float64
+}
+
+func (pr Projection) metresPerDegree() (lat, lon float64) {
+	lat = earthRadius * math.Pi / 180
+	return lat, lat * math.Cos(pr.Origin.Lat*math.Pi/180)
+}
+
+// ToCell returns the cell containing ll. X grows eastwards and Y grows
+// southwards from the origin; the result may lie outside the grid.
+func (pr Projection) ToCell(ll LatLon) Point {
+	mLat, mLon := pr.metresPerDegree()
+	x := (ll.Lon - pr.Origin.Lon) * mLon / pr.CellSize
+	y := (pr.Origin.Lat - ll.Lat) * mLat / pr.CellSize
+	return Point{int(math.Floor(x)), int(math.Floor(y))}
+}
+
+// ToLatLon returns the centre of cell p.
+func (pr Projection) ToLatLon(p Point) LatLon {
+	mLat, mLon := pr.metresPerDegree()
+	return LatLon{
+		Lat: pr.Origin.Lat - (float64(p.Y)+0.5)*pr.CellSize/mLat,
+		Lon: pr.Origin.Lon + (float64(p.X)+0.5)*pr.CellSize/mLon,
+	}
+}
+
+// Track returns the centres of the cells along p.
+func (pr Projection) Track(p Path) []LatLon {
+	out := make([]LatLon, len(p.Points))
+	for i, pt := range p.Points {
+		out[i] = pr.ToLatLon(pt)
+	}
+	return out
+}
+
+// WriteGeoJSON writes track as a GeoJSON LineString feature.
+func WriteGeoJSON(w io.Writer, track []LatLon) error {
+	coords := make([][2]float64, len(track))
+	for i, ll := range track {
+		coords[i] = [2]float64{ll.Lon, ll.Lat}
+	}
+	type geometry struct {
+		Type        string       `json:"type"`
+		Coordinates [][2]float64 `json:"coordinates"`
+	}
+	return json.NewEncoder(w).Encode(struct {
+		Type       string            `json:"type"`
+		Geometry   geometry          `json:"geometry"`
+		Properties map[string]string `json:"properties"`
+	}{"Feature", geometry{"LineString", coords}, map[string]string{}})
+}
@@ -0,0 +1,3 @@
+module github.com/oskjuanja/Dijkstra-Path-Finder
+
+go 1.22
@@ -0,0 +1,416 @@
+// Package dijkstrapf provides a grid graph that can be created and edited
+// cell by cell, and solvers that find the shortest path between a start and
+// a target cell of that grid.
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Cell markers stored in the grid matrix.
+const (
+	Empty = iota
+	Wall
+	Start
+	Goal
+)
+
+var (
+	ErrOutOfBounds  = errors.New("dijkstrapf: point out of bounds")
+	ErrNoStart      = errors.New("dijkstrapf: no start cell set")
+	ErrNoGoal       = errors.New("dijkstrapf: no goal cell set")
+	ErrNoPath       = errors.New("dijkstrapf: no path between start and goal")
+	ErrBadWeight    = errors.New("dijkstrapf: cell weight must be positive")
+	ErrNegativeCost = errors.New("dijkstrapf: edge cost must not be negative")
+)
+
+// Point is a cell coordinate. X grows to the right, Y grows downwards.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+// Graph is a rectangular grid of cells. Every non-wall cell is a node and
+// neighbouring non-wall cells are connected by edges whose cost is the
+// weight of the cell being entered.
+type Graph struct {
+	width, height int
+	gridMatrix    [][]int
+	weights       [][]float64
+
+	// terrainAt holds 1 + the index into terrains for cells with a named
+	// terrain, 0 otherwise. It is allocated on first use.
+	terrains  []Terrain
+	terrainAt [][]uint8
+
+	start, goal       Point
+	hasStart, hasGoal bool
+	diagonal          bool
+	corners           CornerRule
+	diagonalCost      float64
+	wrap              bool
+
+	// adjList is rebuilt lazily from the grid whenever stale is set.
+	// extra holds edges added on top of the grid neighbourhood, keyed by
+	// their source node; removed holds the grid moves RemoveEdge took
+	// out, keyed by source and target node. pruned marks the cells FillDeadEnds cut out of
+	// adjList; it is dropped with the next rebuild.
+	adjList [][]edge
+	extra   map[int][]edge
+	removed map[[2]int]bool
+	pruned  []bool
+	stale   bool
+	// mesh caches the navigation mesh of adjList.
+	mesh *NavMesh
+	// bridges lists the bridges whose edges are in extra.
+	bridges []Bridge
+	// doors holds the door cells, keyed by node. keys and locks map the
+	// cells holding a key or a colored lock to an index into keyColors.
+	doors     map[int]door
+	keys      map[int]int
+	locks     map[int]int
+	keyColors []string
+	// risk holds the blockage probability of every cell once one is set.
+	risk []float64
+	// fingerprint caches Fingerprint while fingerprinted is set; edits
+	// clear it.
+	fingerprint   uint64
+	fingerprinted bool
+
+	// Results of the most recent solve. reversed is set when the search
+	// ran from the goal, making prev point towards the goal.
+	dist     []float64
+	prev     []int
+	closed   []bool
+	reversed bool
+	stats    Stats
+	// tracing is the parent of the spans recorded during a solve.
+	tracing spanParent
+
+	observers []func(Change)
+}
+
+type edge struct {
+	to   int
+	cost float64
+}
+
+// NewGraph returns an empty width x height grid with every cell weight set
+// to 1.
+func NewGraph(width, height int) *Graph {
+	if width < 0 || height < 0 {
+		panic("dijkstrapf: negative grid dimensions")
+	}
+	g := &Graph{
+		width:      width,
+		height:     height,
+		gridMatrix: make([][]int, height),
+		weights:    make([][]float64, height),
+		terrains:   append([]Terrain(nil), DefaultTerrains...),
+		stale:      true,
+	}
+	for y := 0; y < height; y++ {
+		g.gridMatrix[y] = make([]int, width)
+		g.weights[y] = make([]float64, width)
+		for x := range g.weights[y] {
+			g.weights[y][x] = 1
+		}
+	}
+	return g
+}
+
+// Width returns the number of columns in the grid.
+func (g *Graph) Width() int { return g.width }
+
+// Height returns the number of rows in the grid.
+func (g *Graph) Height() int { return g.height }
+
+// InBounds reports whether p lies inside the grid.
+func (g *Graph) InBounds(p Point) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < g.width && p.Y < g.height
+}
+
+// Cell returns the marker stored at p.
+func (g *Graph) Cell(p Point) int {
+	if !g.InBounds(p) {
+		return Wall
+	}
+	return g.gridMatrix[p.Y][p.X]
+}
+
+// IsWall reports whether p is a wall. Points outside the grid count as walls.
+func (g *Graph) IsWall(p Point) bool {
+	return g.Cell(p) == Wall
+}
+
+// SetWall turns p into a wall, or back into an empty cell. Placing a wall on
+// the start or goal cell removes that marker.
+func (g *Graph) SetWall(p Point, wall bool) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	if wall {
+		g.clearMarker(p)
+		g.clearTerrain(p)
+		delete(g.doors, g.id(p))
+		delete(g.locks, g.id(p))
+		delete(g.keys, g.id(p))
+		g.gridMatrix[p.Y][p.X] = Wall
+	} else if g.gridMatrix[p.Y][p.X] == Wall {
+		g.gridMatrix[p.Y][p.X] = Empty
+	}
+	g.stale = true
+	g.changed(ChangeWall, p)
+	return nil
+}
+
+// Weight returns the cost of entering p.
+func (g *Graph) Weight(p Point) float64 {
+	if !g.InBounds(p) {
+		return math.Inf(1)
+	}
+	return g.weights[p.Y][p.X]
+}
+
+// SetWeight sets the cost of entering p.
+func (g *Graph) SetWeight(p Point, w float64) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	if !(w > 0) {
+		return ErrBadWeight
+	}
+	g.clearTerrain(p)
+	g.weights[p.Y][p.X] = w
+	g.stale = true
+	g.changed(ChangeWeight, p)
+	return nil
+}
+
+// Start returns the start cell and whether one has been set.
+func (g *Graph) Start() (Point, bool) { return g.start, g.hasStart }
+
+// Goal returns the goal cell and whether one has been set.
+func (g *Graph) Goal() (Point, bool) { return g.goal, g.hasGoal }
+
+// SetStart moves the start marker to p.
+func (g *Graph) SetStart(p Point) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	old, had := g.start, g.hasStart
+	if had {
+		g.gridMatrix[old.Y][old.X] = Empty
+	}
+	g.clearMarker(p)
+	g.gridMatrix[p.Y][p.X] = Start
+	g.start, g.hasStart = p, true
+	g.stale = true
+	if had && old != p {
+		g.changed(ChangeMarker, old)
+	}
+	g.changed(ChangeMarker, p)
+	return nil
+}
+
+// SetGoal moves the goal marker to p.
+func (g *Graph) SetGoal(p Point) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	old, had := g.goal, g.hasGoal
+	if had {
+		g.gridMatrix[old.Y][old.X] = Empty
+	}
+	g.clearMarker(p)
+	g.gridMatrix[p.Y][p.X] = Goal
+	g.goal, g.hasGoal = p, true
+	g.stale = true
+	if had && old != p {
+		g.changed(ChangeMarker, old)
+	}
+	g.changed(ChangeMarker, p)
+	return nil
+}
+
+// clearMarker forgets the start or goal if it sits on p.
+func (g *Graph) clearMarker(p Point) {
+	if g.hasStart && g.start == p {
+		g.hasStart = false
+	}
+	if g.hasGoal && g.goal == p {
+		g.hasGoal = false
+	}
+}
+
+// Diagonal reports whether diagonal moves are allowed.
+func (g *Graph) Diagonal() bool { return g.diagonal }
+
+// SetDiagonal enables or disables diagonal moves.
+func (g *Graph) SetDiagonal(on bool) {
+	g.diagonal = on
+	g.stale = true
+	g.changed(ChangeLayout, Point{-1, -1})
+}
+
+// DiagonalCost returns the factor diagonal steps cost relative to
+// orthogonal ones.
+func (g *Graph) DiagonalCost() float64 {
+	if g.diagonalCost == 0 {
+		return 1
+	}
+	return g.diagonalCost
+}
+
+// SetDiagonalCost makes a diagonal step cost ratio times the weight of the
+// cell entered, instead of the weight alone. math.Sqrt2 gives true octile
+// distances, so paths across open ground run straight instead of through
+// equally cheap staircases; 1.4 approximates it. ratio must lie between 1
+// and 2.
+func (g *Graph) SetDiagonalCost(ratio float64) error {
+	if !(ratio >= 1 && ratio <= 2) {
+		return fmt.Errorf("%w: diagonal cost %g is not between 1 and 2", ErrBadWeight, ratio)
+	}
+	g.diagonalCost = ratio
+	g.stale = true
+	g.changed(ChangeLayout, Point{-1, -1})
+	return nil
+}
+
+// gridDistance returns the cost of the cheapest route dx columns and dy
+// rows long on an empty grid of unit weights.
+func (g *Graph) gridDistance(dx, dy int) float64 {
+	if !g.diagonal {
+		return float64(dx + dy)
+	}
+	lo, hi := min(dx, dy), max(dx, dy)
+	return g.DiagonalCost()*float64(lo) + float64(hi-lo)
+}
+
+// Wrap reports whether the grid wraps around at its edges.
+func (g *Graph) Wrap() bool { return g.wrap }
+
+// SetWrap makes the grid toroidal: the left edge neighbours the right edge
+// and the top edge neighbours the bottom edge, as in Pac-Man.
+func (g *Graph) SetWrap(on bool) {
+	g.wrap = on
+	g.stale = true
+	g.changed(ChangeLayout, Point{-1, -1})
+}
+
+// delta returns the per-axis distance between a and b, taking the short way
+// around on wrapping grids.
+func (g *Graph) delta(a, b Point) (dx, dy int) {
+	dx, dy = abs(a.X-b.X), abs(a.Y-b.Y)
+	if g.wrap {
+		dx = min(dx, g.width-dx)
+		dy = min(dy, g.height-dy)
+	}
+	return dx, dy
+}
+
+// PrintGrid writes the grid matrix to standard output, one row per line.
+func (g *Graph) PrintGrid() {
+	for _, row := range g.gridMatrix {
+		fmt.Println(row)
+	}
+}
+
+func (g *Graph) id(p Point) int { return p.Y*g.width + p.X }
+
+func (g *Graph) point(id int) Point { return Point{id % g.width, id / g.width} }
+
+func (g *Graph) nodeCount() int { return g.width * g.height }
+
+var (
+	orthogonal = []Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	diagonals  = []Point{{1, -1}, {1, 1}, {-1, 1}, {-1, -1}}
+)
+
+// adjacency returns the adjacency list, rebuilding it if the grid changed.
+func (g *Graph) adjacency() [][]edge {
+	if g.stale || g.adjList == nil {
+		g.buildAdjacency()
+	}
+	return g.adjList
+}
+
+func (g *Graph) buildAdjacency() {
+	g.fingerprinted = false
+	g.adjList = make([][]edge, g.nodeCount())
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			if g.IsWall(p) {
+				continue
+			}
+			g.adjList[g.id(p)] = g.neighbours(p)
+		}
+	}
+	for from, edges := range g.extra {
+		if g.IsWall(g.point(from)) {
+			continue
+		}
+		for _, e := range edges {
+			if e, ok := g.throughDoor(e); ok {
+				g.adjList[from] = append(g.adjList[from], e)
+			}
+		}
+	}
+	g.pruned = nil
+	g.mesh = nil
+	g.stale = false
+}
+
+// addExtraEdge adds a directed edge from a to b on top of the grid
+// neighbourhood. It only takes effect while both cells are walkable.
+func (g *Graph) addExtraEdge(a, b Point, cost float64) {
+	if g.extra == nil {
+		g.extra = make(map[int][]edge)
+	}
+	from := g.id(a)
+	g.extra[from] = append(g.extra[from], edge{g.id(b), cost})
+	g.stale = true
+	g.changed(ChangeLayout, Point{-1, -1})
+}
+
+func (g *Graph) neighbours(p Point) []edge {
+	var out []edge
+	ratio := g.DiagonalCost()
+	add := func(d Point, scale float64) {
+		q := Point{p.X + d.X, p.Y + d.Y}
+		if g.wrap {
+			q = Point{(q.X + g.width) % g.width, (q.Y + g.height) % g.height}
+			if q == p {
+				return
+			}
+		}
+		if !g.InBounds(q) || g.IsWall(q) || math.IsInf(g.weights[q.Y][q.X], 1) {
+			return
+		}
+		if g.removed[[2]int{g.id(p), g.id(q)}] {
+			return
+		}
+		c := scale*g.weights[q.Y][q.X] + g.doorCost(g.id(q))
+		if math.IsInf(c, 1) {
+			return
+		}
+		out = append(out, edge{g.id(q), c})
+	}
+	for _, d := range orthogonal {
+		add(d, 1)
+	}
+	if g.diagonal {
+		for _, d := range diagonals {
+			if g.cornerAllowed(p, d) {
+				add(d, ratio)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrNotSolved is returned by renderings that need a search result when
+// the graph has not been solved yet.
+var ErrNotSolved = errors.New("dijkstrapf: graph has not been solved")
+
+// HeatmapStyle selects how WriteHeatmap draws distances.
+type HeatmapStyle int
+
+const (
+	// HeatDigits draws the distance as a digit 0-9 scaled to the largest
+	// distance.
+	HeatDigits HeatmapStyle = iota
+	// HeatBlocks draws light to dark Unicode shade blocks.
+	HeatBlocks
+	// HeatColor draws ANSI 256-color backgrounds from blue (near) to red
+	// (far).
+	HeatColor
+)
+
+var (
+	shadeBlocks = []string{"░", "▒", "▓", "█"}
+	heatColors  = []int{21, 27, 33, 39, 45, 51, 50, 49, 48, 82, 118, 154, 190, 226, 220, 214, 208, 202, 196}
+)
+
+// WriteHeatmap draws the distance field of the most recent solve. Walls are
+// drawn as '#' and cells the search did not settle as blank. Solve with
+// WithFullMap first to see every reachable cell.
+func (g *Graph) WriteHeatmap(w io.Writer, style HeatmapStyle) error {
+	if g.closed == nil {
+		return ErrNotSolved
+	}
+	far := 0.0
+	for id, d := range g.dist {
+		if g.closed[id] && d > far {
+			far = d
+		}
+	}
+	bw := bufio.NewWriter(w)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			d, ok := g.Distance(p)
+			if g.IsWall(p) {
+				bw.WriteByte(SymbolWall)
+				continue
+			}
+			if !ok {
+				bw.WriteByte(' ')
+				continue
+			}
+			writeHeat(bw, style, d, far)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// heatLevel scales d in [0, far] to [0, n).
+func heatLevel(d, far float64, n int) int {
+	if far <= 0 {
+		return 0
+	}
+	return min(n-1, int(math.Floor(d/far*float64(n))))
+}
+
+func writeHeat(w *bufio.Writer, style HeatmapStyle, d, far float64) {
+	switch style {
+	case HeatBlocks:
+		w.WriteString(shadeBlocks[heatLevel(d, far, len(shadeBlocks))])
+	case HeatColor:
+		fmt.Fprintf(w, "\x1b[48;5;%dm \x1b[0m", heatColors[heatLevel(d, far, len(heatColors))])
+	default:
+		w.WriteByte(byte('0' + heatLevel(d, far, 10)))
+	}
+}
@@ -0,0 +1,64 @@
+// Package heuristic provides distance estimates for the informed solvers
+// of package dijkstrapf. Pass them with dijkstrapf.WithHeuristic, and check
+// a heuristic against a particular grid with Graph.CheckHeuristic.
+//
+// Each function measures the distance on a grid whose cells all cost 1 to
+// enter. Use Scaled when every cell costs at least some other amount. None
+// of them knows about wrapping grids, where they may overestimate.
+package heuristic
+
+import (
+	"math"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+// Zero estimates nothing, which turns A* into Dijkstra's algorithm.
+func Zero(from, goal dijkstrapf.Point) float64 { return 0 }
+
+// Manhattan is the number of orthogonal moves between the cells. It is
+// exact on an empty grid without diagonal moves and overestimates with
+// them.
+func Manhattan(from, goal dijkstrapf.Point) float64 {
+	dx, dy := delta(from, goal)
+	return dx + dy
+}
+
+// Euclidean is the straight-line distance. It never overestimates, but is
+// weaker than the grid distances.
+func Euclidean(from, goal dijkstrapf.Point) float64 {
+	dx, dy := delta(from, goal)
+	return math.Hypot(dx, dy)
+}
+
+// Chebyshev is the number of moves between the cells when diagonal moves
+// cost the same as orthogonal ones.
+func Chebyshev(from, goal dijkstrapf.Point) float64 {
+	dx, dy := delta(from, goal)
+	return max(dx, dy)
+}
+
+// Octile is the distance when diagonal moves cost √2.
+func Octile(from, goal dijkstrapf.Point) float64 {
+	return OctileCost(math.Sqrt2)(from, goal)
+}
+
+// OctileCost returns the distance when diagonal moves cost diagonal, such
+// as 1.4 for the common 14/10 integer approximation of √2.
+func OctileCost(diagonal float64) dijkstrapf.HeuristicFunc {
+	return func(from, goal dijkstrapf.Point) float64 {
+		dx, dy := delta(from, goal)
+		lo, hi := min(dx, dy), max(dx, dy)
+		return diagonal*lo + (hi - lo)
+	}
+}
+
+// Scaled multiplies h by k, typically the cheapest cell weight of the
+// grid.
+func Scaled(h dijkstrapf.HeuristicFunc, k float64) dijkstrapf.HeuristicFunc {
+	return func(from, goal dijkstrapf.Point) float64 { return k * h(from, goal) }
+}
+
+func delta(a, b dijkstrapf.Point) (float64, float64) {
+	return math.Abs(float64(a.X - b.X)), math.Abs(float64(a.Y - b.Y))
+}
@@ -0,0 +1,52 @@
+package heuristic_test
+
+import (
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+	"github.com/oskjuanja/Dijkstra-Path-Finder/heuristic"
+)
+
+const checkMap = `S.....#...
+.####.#.#.
+.#....#.#.
+.#.####.#.
+...#....#G
+`
+
+func TestCheckHeuristic(t *testing.T) {
+	tests := []struct {
+		name                   string
+		h                      dijkstrapf.HeuristicFunc
+		diagonal               bool
+		admissible, consistent bool
+	}{
+		{"zero", heuristic.Zero, false, true, true},
+		{"manhattan", heuristic.Manhattan, false, true, true},
+		{"euclidean", heuristic.Euclidean, false, true, true},
+		{"chebyshev", heuristic.Chebyshev, true, true, true},
+		{"manhattan diagonal", heuristic.Manhattan, true, false, false},
+		{"octile diagonal", heuristic.Octile, true, false, false},
+		{"scaled", heuristic.Scaled(heuristic.Manhattan, 3), false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(checkMap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			g.SetDiagonal(tt.diagonal)
+			r, err := g.CheckHeuristic(tt.h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Admissible() != tt.admissible {
+				t.Errorf("Admissible() = %v, want %v: %v", r.Admissible(), tt.admissible, r.Overestimates)
+			}
+			if r.Consistent() != tt.consistent {
+				t.Errorf("Consistent() = %v, want %v: %v", r.Consistent(), tt.consistent, r.Inconsistent)
+			}
+		})
+	}
+}
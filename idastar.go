@@ -0,0 +1,187 @@
+package dijkstrapf
+
+import (
+	"math"
+	"slices"
+)
+
+// idaGrowth is the least factor IDA* raises its bound by between
+// iterations. Raising it only to the next f-value takes an iteration per
+// distinct path cost, which with real-valued weights is nearly one per
+// path.
+const idaGrowth = 1.1
+
+// FindPathIDAStar runs iterative-deepening A*. It needs memory only for
+// the path being explored, not for per-cell distance arrays, which makes it
+// usable on grids too large for the other solvers, at the price of
+// re-expanding cells many times. Without a record of visited cells its
+// running time grows exponentially with the number of alternative routes,
+// and proving that the goal is unreachable means trying every one of them,
+// so it is not registered for Solve. It gives up with ErrNoPath once every
+// path it is still to try costs more than the dearest simple path through
+// the grid could, and honours WithContext, which is the practical way to
+// bound a solve on a large grid. The bound grows by at least idaGrowth a
+// round, and the round that first reaches the goal carries on as a
+// branch-and-bound search for cheaper paths, so the path is still the
+// shortest for an admissible heuristic. It leaves no search tree behind;
+// Explored, Distance and PathTo know nothing about an IDA* solve.
+func (g *Graph) FindPathIDAStar(opts ...Option) (Path, error) {
+	// IDA* keeps no per-cell state, so WithMemoryLimit does not apply.
+	noLimit := func(o *Options) { o.MemoryLimit = 0 }
+	return g.run("idastar", idastar, append(opts[:len(opts):len(opts)], noLimit))
+}
+
+func idastar(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	limit, err := g.simplePathLimit(o)
+	if err != nil {
+		return Path{}, err
+	}
+	s := &deepener{g: g, o: o, h: g.heuristic(o), eps: o.epsilon(), dst: dst,
+		onPath: map[int]bool{src: true}, path: []int{src}}
+	bound := s.f(src, 0)
+	for {
+		if o.Context != nil {
+			if err := o.Context.Err(); err != nil {
+				return Path{}, err
+			}
+		}
+		s.cut = math.Inf(1)
+		next, err := s.search(0, bound)
+		if err != nil {
+			return Path{}, err
+		}
+		if s.best != nil {
+			break
+		}
+		// Every path the next iteration would try is a simple path that
+		// already costs more than limit, so none of them reaches the goal.
+		if math.IsInf(next, 1) || s.cut > limit {
+			g.finish(o, dst, math.Inf(1))
+			return Path{}, ErrNoPath
+		}
+		bound = max(next, bound*idaGrowth)
+	}
+
+	g.finish(o, dst, s.cost)
+	points := make([]Point, len(s.best))
+	for i, id := range s.best {
+		points[i] = g.point(id)
+	}
+	return Path{Points: points, Cost: s.cost}, nil
+}
+
+// deepener holds the state of one IDA* solve: the path currently being
+// explored and the cells on it.
+type deepener struct {
+	g      *Graph
+	o      *Options
+	h      HeuristicFunc
+	eps    float64
+	dst    int
+	path   []int
+	onPath map[int]bool
+	// best is the cheapest path to the goal found so far, of cost cost.
+	best []int
+	cost float64
+	// cut is the least cost of a path cut off by the bound in the current
+	// iteration.
+	cut float64
+}
+
+func (s *deepener) f(node int, d float64) float64 {
+	return d + s.eps*s.h(s.g.point(node), s.g.goal)
+}
+
+// search extends the path, which costs d so far, by depth-first search
+// within bound, recording in best any path to the goal cheaper than the
+// best so far. It returns the smallest f-value that exceeded the bound.
+func (s *deepener) search(d, bound float64) (float64, error) {
+	g := s.g
+	cur := s.path[len(s.path)-1]
+	f := s.f(cur, d)
+	if s.best != nil && f >= s.cost {
+		return math.Inf(1), nil
+	}
+	if f > bound {
+		s.cut = min(s.cut, d)
+		return f, nil
+	}
+	g.settle(s.o, cur, d)
+	if err := g.cancelled(s.o); err != nil {
+		return 0, err
+	}
+	if s.o.expansionBudget > 0 && g.stats.Expanded > s.o.expansionBudget {
+		return 0, errOverBudget
+	}
+	if cur == s.dst {
+		s.best, s.cost = slices.Clone(s.path), d
+		return math.Inf(1), nil
+	}
+	next := math.Inf(1)
+	for _, e := range g.edgesFrom(cur) {
+		if s.onPath[e.to] {
+			continue
+		}
+		c, err := g.stepCost(s.o, cur, e)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsInf(c, 1) {
+			continue
+		}
+		s.path = append(s.path, e.to)
+		s.onPath[e.to] = true
+		t, err := s.search(d+c, bound)
+		if err != nil {
+			return 0, err
+		}
+		delete(s.onPath, e.to)
+		s.path = s.path[:len(s.path)-1]
+		next = min(next, t)
+	}
+	return next, nil
+}
+
+// simplePathLimit returns a cost no simple path through g exceeds: the sum
+// over the cells of the dearest passable step out of each. If the goal is
+// reachable at all, the cheapest path to it costs at most this much.
+func (g *Graph) simplePathLimit(o *Options) (float64, error) {
+	limit := 0.0
+	for node := range g.nodeCount() {
+		dearest := 0.0
+		for _, e := range g.edgesFrom(node) {
+			c, err := g.stepCost(o, node, e)
+			if err != nil {
+				return 0, err
+			}
+			if !math.IsInf(c, 1) {
+				dearest = max(dearest, c)
+			}
+		}
+		limit += dearest
+	}
+	return limit, nil
+}
+
+// edgesFrom returns the edges leaving node without building the whole
+// adjacency list.
+func (g *Graph) edgesFrom(node int) []edge {
+	p := g.point(node)
+	if g.IsWall(p) {
+		return nil
+	}
+	out := g.neighbours(p)
+	for _, e := range g.extra[node] {
+		if e, ok := g.throughDoor(e); ok {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package dijkstrapf_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestIDAStarUnreachable(t *testing.T) {
+	g, err := dijkstrapf.LoadGrid(strings.NewReader("S..\n.##\n.#G\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.FindPathIDAStar(); !errors.Is(err, dijkstrapf.ErrNoPath) {
+		t.Fatalf("err = %v, want ErrNoPath", err)
+	}
+}
+
+func TestIDAStarWeighted(t *testing.T) {
+	tests := []struct {
+		name     string
+		grid     string
+		diagonal bool
+		budget   int
+	}{
+		{"weighted", "S5..\n.#5.\n.9.3\n2..G\n", false, 1000},
+		{
+			"octile",
+			`S89#83492334
+##6253925852
+3398526#9194
+369511344##8
+98394678611#
+623269G#5795
+989317#64138
+56946#15588#
+583#74117322
+7#7173215877
+`,
+			true, 100000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tt.grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.diagonal {
+				g.SetDiagonal(true)
+				g.SetWrap(true)
+				g.SetCornerRule(dijkstrapf.CornerNever)
+				if err := g.SetDiagonalCost(math.Sqrt2); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := g.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := g.FindPathIDAStar()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := g.Stats().Expanded; n > tt.budget {
+				t.Fatalf("expanded %d nodes, budget %d", n, tt.budget)
+			}
+			if math.Abs(got.Cost-want.Cost) > 1e-9 {
+				t.Fatalf("cost %g, want %g", got.Cost, want.Cost)
+			}
+			if err := g.ValidatePath(got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestMemoryFallbackCancelled(t *testing.T) {
+	rows := make([]string, 10)
+	for y := range rows {
+		rows[y] = strings.Repeat(".", 10)
+	}
+	rows[0] = "S" + rows[0][1:]
+	rows[7] = strings.Repeat(".", 7) + "###"
+	rows[8] = strings.Repeat(".", 7) + "#.."
+	rows[9] = strings.Repeat(".", 7) + "#.G"
+	g, err := dijkstrapf.LoadGrid(strings.NewReader(strings.Join(rows, "\n") + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.FindPath(dijkstrapf.WithMemoryLimit(1), dijkstrapf.WithMemoryFallback(), dijkstrapf.WithContext(ctx))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, dijkstrapf.ErrNoPath) && !errors.Is(err, dijkstrapf.ErrMemoryLimit) {
+			t.Fatalf("err = %v, want a deadline, ErrNoPath or ErrMemoryLimit", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("solve ignored its context")
+	}
+}
+
+func TestMemoryFallbackWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		grid    string
+		wrap    bool
+		wantErr error
+	}{
+		{"small", "S5..\n.#5.\n.9.3\n2..G\n", false, nil},
+		{"open", "S........\n.5.9.2...\n.........\n..7.3...G\n", false, nil},
+		{"too slow", `S89#83492334
+##6253925852
+3398526#9194
+369511344##8
+98394678611#
+623269G#5795
+989317#64138
+56946#15588#
+583#74117322
+7#7173215877
+`, true, dijkstrapf.ErrMemoryLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tt.grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			g.SetDiagonal(true)
+			g.SetWrap(tt.wrap)
+			g.SetCornerRule(dijkstrapf.CornerNever)
+			if err := g.SetDiagonalCost(math.Sqrt2); err != nil {
+				t.Fatal(err)
+			}
+			want, err := g.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := g.FindPath(dijkstrapf.WithMemoryLimit(1), dijkstrapf.WithMemoryFallback())
+			// The fallback may expand 100 cells per cell of the grid, and
+			// notices only after the one over.
+			if n, cells := g.Stats().Expanded, g.Width()*g.Height(); n > 100*cells+1 {
+				t.Fatalf("expanded %d nodes on a grid of %d cells", n, cells)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got.Cost-want.Cost) > 1e-9 {
+				t.Fatalf("cost %g, want %g", got.Cost, want.Cost)
+			}
+			if err := g.ValidatePath(got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF for LoadImage
+	_ "image/jpeg" // register JPEG for LoadImage
+	"image/png"
+	"io"
+	"math"
+)
+
+// ImageCost says how FromImage turns the grey level of a pixel, from 0 for
+// black to 1 for white, into a cell.
+type ImageCost struct {
+	// Black and White are the weights of black and white pixels; grey
+	// pixels get weights in between, in proportion. Both zero means black
+	// weighs 10 and white 1, so that light areas are cheap.
+	Black, White float64
+	// Pixels darker than WallBelow, or lighter than WallAbove, become
+	// walls. Zero leaves either rule out.
+	WallBelow, WallAbove float64
+}
+
+// FromImage turns img into a grid with one cell per pixel whose weight
+// follows the pixel's grey level, so that a GIS cost raster or a
+// hand-painted heatmap drives the routing directly. Coloured pixels are
+// taken by their luminance. The grid has no start or goal.
+func FromImage(img image.Image, c ImageCost) (*Graph, error) {
+	if c.Black == 0 && c.White == 0 {
+		c.Black, c.White = 10, 1
+	}
+	if !(c.Black > 0) || !(c.White > 0) {
+		return nil, fmt.Errorf("%w: black %g, white %g", ErrBadWeight, c.Black, c.White)
+	}
+	b := img.Bounds()
+	g := NewGraph(b.Dx(), b.Dy())
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			v := float64(color.Gray16Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray16).Y) / 0xffff
+			if c.WallBelow > 0 && v < c.WallBelow || c.WallAbove > 0 && v > c.WallAbove {
+				g.gridMatrix[y][x] = Wall
+				continue
+			}
+			g.weights[y][x] = c.Black + (c.White-c.Black)*v
+		}
+	}
+	return g, nil
+}
+
+// LoadImage decodes a PNG, JPEG or GIF image from r and turns it into a
+// grid with FromImage.
+func LoadImage(r io.Reader, c ImageCost) (*Graph, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromImage(img, c)
+}
+
+// DistanceImage renders the distance field of the most recent solve as a
+// grey image with one pixel per cell: the start, or the goal after a
+// reverse search, is white and the farthest settled cell the darkest
+// grey, with the distances in between scaled linearly. Walls and cells the
+// search did not settle are black. Solve with WithFullMap first to cover
+// every reachable cell.
+func (g *Graph) DistanceImage() (*image.Gray, error) {
+	if g.closed == nil {
+		return nil, ErrNotSolved
+	}
+	far := 0.0
+	for id, d := range g.dist {
+		if g.closed[id] && d > far {
+			far = d
+		}
+	}
+	img := image.NewGray(image.Rect(0, 0, g.width, g.height))
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			d, ok := g.Distance(Point{x, y})
+			if !ok || g.IsWall(Point{x, y}) {
+				continue
+			}
+			level := 255.0
+			if far > 0 {
+				level -= math.Round(254 * d / far)
+			}
+			img.SetGray(x, y, color.Gray{uint8(level)})
+		}
+	}
+	return img, nil
+}
+
+// WriteDistancePNG writes DistanceImage to w as a PNG.
+func (g *Graph) WriteDistancePNG(w io.Writer) error {
+	img, err := g.DistanceImage()
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// WriteDistancePGM writes DistanceImage to w as a binary PGM, a format
+// simple enough for any image tool or script to read.
+func (g *Graph) WriteDistancePGM(w io.Writer) error {
+	img, err := g.DistanceImage()
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P5\n%d %d\n255\n", g.width, g.height)
+	for y := 0; y < g.height; y++ {
+		bw.Write(img.Pix[y*img.Stride : y*img.Stride+g.width])
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,120 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"image"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// isochroneSymbols mark the bands in WriteIsochrones output.
+const isochroneSymbols = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Isochrones splits the cells reached by a solve into bands by the cost of
+// reaching them, for maps of everything reachable within 10, 20 or 30
+// steps. It is computed once by Graph.Isochrones and does not follow later
+// solves.
+type Isochrones struct {
+	width, height int
+	thresholds    []float64
+	wall          []bool
+	// band holds the index of the first threshold each cell is within, or
+	// -1.
+	band []int
+}
+
+// Isochrones bands the distance field of the most recent solve by the
+// given thresholds: cell p is within threshold t if the solve settled it
+// at a cost of at most t. Solve with WithFullMap first, or cells beyond
+// the goal are left out.
+func (g *Graph) Isochrones(thresholds ...float64) (*Isochrones, error) {
+	if g.closed == nil {
+		return nil, ErrNotSolved
+	}
+	t := slices.Clone(thresholds)
+	slices.Sort(t)
+	iso := &Isochrones{
+		width: g.width, height: g.height, thresholds: t,
+		wall: make([]bool, g.nodeCount()), band: make([]int, g.nodeCount()),
+	}
+	for id := range iso.band {
+		iso.band[id] = -1
+		iso.wall[id] = g.IsWall(g.point(id))
+		if !g.closed[id] {
+			continue
+		}
+		if i, _ := slices.BinarySearch(t, g.dist[id]); i < len(t) {
+			iso.band[id] = i
+		}
+	}
+	return iso, nil
+}
+
+// Thresholds returns the thresholds of the bands, in increasing order.
+func (iso *Isochrones) Thresholds() []float64 { return slices.Clone(iso.thresholds) }
+
+// Band returns the index into Thresholds of the least threshold p is
+// within, or -1 if it is within none.
+func (iso *Isochrones) Band(p Point) int {
+	if p.X < 0 || p.Y < 0 || p.X >= iso.width || p.Y >= iso.height {
+		return -1
+	}
+	return iso.band[p.Y*iso.width+p.X]
+}
+
+// Within returns the cells within the i-th threshold, those of band i and
+// of every band before it, in grid order.
+func (iso *Isochrones) Within(i int) []Point {
+	var out []Point
+	for id, b := range iso.band {
+		if b >= 0 && b <= i {
+			out = append(out, Point{id % iso.width, id / iso.width})
+		}
+	}
+	return out
+}
+
+// WriteIsochrones draws every cell by its band: '0' for the first, '1' for
+// the next, then on through the digits and the letters a to z, '+' past
+// those. Walls are drawn as '#' and cells within no threshold as '.'.
+func (iso *Isochrones) WriteIsochrones(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < iso.height; y++ {
+		for x := 0; x < iso.width; x++ {
+			id := y*iso.width + x
+			switch b := iso.band[id]; {
+			case iso.wall[id]:
+				bw.WriteByte(SymbolWall)
+			case b < 0:
+				bw.WriteByte('.')
+			case b < len(isochroneSymbols):
+				bw.WriteByte(isochroneSymbols[b])
+			default:
+				bw.WriteByte('+')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Image draws the bands as a grey image with one pixel per cell, the first
+// band white and each later one darker. Walls and cells within no
+// threshold are black.
+func (iso *Isochrones) Image() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, iso.width, iso.height))
+	n := len(iso.thresholds)
+	for id, b := range iso.band {
+		if b < 0 || iso.wall[id] {
+			continue
+		}
+		img.Pix[id/iso.width*img.Stride+id%iso.width] = uint8(255 - 191*b/max(n-1, 1))
+	}
+	return img
+}
+
+// WritePNG writes Image to w as a PNG.
+func (iso *Isochrones) WritePNG(w io.Writer) error {
+	return png.Encode(w, iso.Image())
+}
@@ -0,0 +1,231 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrBadEdit is wrapped by every error ApplyEdit returns for a malformed
+// edit command.
+var ErrBadEdit = errors.New("dijkstrapf: malformed edit")
+
+// ApplyEdit applies one edit command to the graph. The commands are
+//
+//	wall X Y             make (X, Y) a wall
+//	clear X Y            make the wall at (X, Y) an empty cell
+//	weight X Y W         set the cost of entering (X, Y) to W
+//	terrain X Y NAME     give (X, Y) the named terrain
+//	start X Y            move the start to (X, Y)
+//	goal X Y             move the goal to (X, Y)
+//	diagonal on|off      allow or forbid diagonal moves
+//	wrap on|off          wrap around the grid edges or not
+//	corners RULE         set the corner rule: always, one-open or never
+//	diagonal-cost R      make diagonal steps cost R times the cell weight
+//	door X Y STATE C     put a door, open, closed or locked, on (X, Y)
+//	                     that costs C to open
+//	no-door X Y          take the door off (X, Y)
+//	risk X Y P           make (X, Y) blocked with probability P
+//	key X Y COLOR        put a key of the named color on (X, Y)
+//	lock X Y COLOR       put a lock of the named color on (X, Y)
+//
+// Blank lines and lines starting with ';' are ignored.
+func (g *Graph) ApplyEdit(line string) error {
+	f := strings.Fields(line)
+	if len(f) == 0 || strings.HasPrefix(f[0], ";") {
+		return nil
+	}
+	switch f[0] {
+	case "diagonal", "wrap":
+		if len(f) != 2 || f[1] != "on" && f[1] != "off" {
+			return fmt.Errorf("%w: want %s on|off", ErrBadEdit, f[0])
+		}
+		if f[0] == "diagonal" {
+			g.SetDiagonal(f[1] == "on")
+		} else {
+			g.SetWrap(f[1] == "on")
+		}
+		return nil
+	case "corners":
+		if len(f) != 2 {
+			return fmt.Errorf("%w: want corners RULE", ErrBadEdit)
+		}
+		r, err := ParseCornerRule(f[1])
+		if err != nil {
+			return fmt.Errorf("%w: unknown corner rule %q", ErrBadEdit, f[1])
+		}
+		g.SetCornerRule(r)
+		return nil
+	case "diagonal-cost":
+		if len(f) != 2 {
+			return fmt.Errorf("%w: want diagonal-cost R", ErrBadEdit)
+		}
+		r, err := strconv.ParseFloat(f[1], 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad diagonal cost %s", ErrBadEdit, f[1])
+		}
+		return g.SetDiagonalCost(r)
+	case "wall", "clear", "start", "goal", "weight", "terrain", "door", "no-door", "risk", "key", "lock":
+	default:
+		return fmt.Errorf("%w: unknown command %q", ErrBadEdit, f[0])
+	}
+
+	want := 3
+	switch f[0] {
+	case "weight", "risk":
+		want = 4
+	case "door":
+		want = 5
+	case "terrain", "key", "lock":
+		want = max(4, len(f))
+	}
+	if len(f) != want {
+		return fmt.Errorf("%w: wrong number of arguments to %s", ErrBadEdit, f[0])
+	}
+	x, errX := strconv.Atoi(f[1])
+	y, errY := strconv.Atoi(f[2])
+	if errX != nil || errY != nil {
+		return fmt.Errorf("%w: bad cell %s %s", ErrBadEdit, f[1], f[2])
+	}
+	p := Point{x, y}
+	switch f[0] {
+	case "wall":
+		return g.SetWall(p, true)
+	case "clear":
+		return g.SetWall(p, false)
+	case "start":
+		return g.SetStart(p)
+	case "goal":
+		return g.SetGoal(p)
+	case "weight":
+		w, err := strconv.ParseFloat(f[3], 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad weight %s", ErrBadEdit, f[3])
+		}
+		return g.SetWeight(p, w)
+	case "door":
+		state := slices.Index(doorStateNames, f[3])
+		if state < 0 {
+			return fmt.Errorf("%w: unknown door state %q", ErrBadEdit, f[3])
+		}
+		c, err := strconv.ParseFloat(f[4], 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad door cost %s", ErrBadEdit, f[4])
+		}
+		return g.SetDoor(p, DoorState(state), c)
+	case "no-door":
+		return g.RemoveDoor(p)
+	case "risk":
+		prob, err := strconv.ParseFloat(f[3], 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad probability %s", ErrBadEdit, f[3])
+		}
+		return g.SetBlockProbability(p, prob)
+	case "key":
+		return g.PlaceKey(p, strings.Join(f[3:], " "))
+	case "lock":
+		return g.PlaceLock(p, strings.Join(f[3:], " "))
+	}
+	return g.SetTerrain(p, strings.Join(f[3:], " "))
+}
+
+// ReplayEdits applies the edit commands read from r, one per line, as
+// written by RecordEdits. It stops at the first command that fails.
+func (g *Graph) ReplayEdits(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		if err := g.ApplyEdit(sc.Text()); err != nil {
+			return fmt.Errorf("dijkstrapf: edit journal line %d: %w", n, err)
+		}
+	}
+	return sc.Err()
+}
+
+// RecordEdits writes an edit command to w for every later change to the
+// grid, so that replaying them with ReplayEdits on a copy of the grid as
+// it is now reconstructs the grid as it will be. Terrain definitions and
+// edges added or removed with AddEdge and RemoveEdge are not recorded.
+// The returned function stops recording and reports the first write error.
+func (g *Graph) RecordEdits(w io.Writer) (stop func() error) {
+	var werr error
+	diagonal, corners, ratio, wrap := g.diagonal, g.corners, g.DiagonalCost(), g.wrap
+	write := func(format string, args ...any) {
+		if werr == nil {
+			_, werr = fmt.Fprintf(w, format+"\n", args...)
+		}
+	}
+	cancel := g.OnChange(func(c Change) {
+		p := c.At
+		switch c.Kind {
+		case ChangeWall:
+			if g.IsWall(p) {
+				write("wall %d %d", p.X, p.Y)
+			} else {
+				write("clear %d %d", p.X, p.Y)
+			}
+		case ChangeWeight:
+			write("weight %d %d %g", p.X, p.Y, g.Weight(p))
+		case ChangeTerrain:
+			if t, ok := g.TerrainAt(p); ok {
+				write("terrain %d %d %s", p.X, p.Y, t.Name)
+			}
+		case ChangeDoor:
+			id := g.id(p)
+			d, ok := g.doors[id]
+			c, colored := g.locks[id]
+			switch {
+			case ok && colored && d.state == DoorLocked:
+				write("lock %d %d %s", p.X, p.Y, g.keyColors[c])
+			case ok:
+				write("door %d %d %v %g", p.X, p.Y, d.state, d.cost)
+			default:
+				write("no-door %d %d", p.X, p.Y)
+			}
+		case ChangeKey:
+			write("key %d %d %s", p.X, p.Y, g.keyColors[g.keys[g.id(p)]])
+		case ChangeRisk:
+			write("risk %d %d %g", p.X, p.Y, g.BlockProbability(p))
+		case ChangeMarker:
+			// The cell a marker left is reported too; only the cell it
+			// moved to needs a command.
+			switch g.Cell(p) {
+			case Start:
+				write("start %d %d", p.X, p.Y)
+			case Goal:
+				write("goal %d %d", p.X, p.Y)
+			}
+		case ChangeLayout:
+			if g.diagonal != diagonal {
+				diagonal = g.diagonal
+				write("diagonal %s", onOff(diagonal))
+			}
+			if g.corners != corners {
+				corners = g.corners
+				write("corners %v", corners)
+			}
+			if g.DiagonalCost() != ratio {
+				ratio = g.DiagonalCost()
+				write("diagonal-cost %g", ratio)
+			}
+			if g.wrap != wrap {
+				wrap = g.wrap
+				write("wrap %s", onOff(wrap))
+			}
+		}
+	})
+	return func() error {
+		cancel()
+		return werr
+	}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
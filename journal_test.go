@@ -0,0 +1,98 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+const journalMap = `S..#....
+.#.#.##.
+.#...#..
+...#...G
+`
+
+func TestRecordEditsRoundTrip(t *testing.T) {
+	p := func(x, y int) dijkstrapf.Point { return dijkstrapf.Point{X: x, Y: y} }
+	tests := []struct {
+		name string
+		edit func(g *dijkstrapf.Graph) error
+	}{
+		{"walls", func(g *dijkstrapf.Graph) error {
+			if err := g.SetWall(p(2, 0), true); err != nil {
+				return err
+			}
+			return g.SetWall(p(3, 0), false)
+		}},
+		{"doors", func(g *dijkstrapf.Graph) error {
+			if err := g.SetDoor(p(3, 1), dijkstrapf.DoorLocked, 0); err != nil {
+				return err
+			}
+			if err := g.SetDoor(p(3, 3), dijkstrapf.DoorClosed, 2.5); err != nil {
+				return err
+			}
+			if err := g.OpenDoor(p(3, 1)); err != nil {
+				return err
+			}
+			if err := g.SetDoor(p(5, 2), dijkstrapf.DoorOpen, 1); err != nil {
+				return err
+			}
+			return g.RemoveDoor(p(5, 2))
+		}},
+		{"keys", func(g *dijkstrapf.Graph) error {
+			// Lock the goal in, so that the path needs the key.
+			if err := g.PlaceLock(p(7, 2), "dark red"); err != nil {
+				return err
+			}
+			if err := g.PlaceLock(p(6, 3), "dark red"); err != nil {
+				return err
+			}
+			return g.PlaceKey(p(0, 3), "dark red")
+		}},
+		{"risk", func(g *dijkstrapf.Graph) error {
+			if err := g.SetBlockProbability(p(2, 2), 0.25); err != nil {
+				return err
+			}
+			if err := g.SetBlockProbability(p(4, 3), 1); err != nil {
+				return err
+			}
+			return g.SetBlockProbability(p(2, 2), 0)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(journalMap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			replica, err := dijkstrapf.LoadGrid(strings.NewReader(journalMap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var journal strings.Builder
+			stop := g.RecordEdits(&journal)
+			if err := tt.edit(g); err != nil {
+				t.Fatal(err)
+			}
+			if err := stop(); err != nil {
+				t.Fatal(err)
+			}
+			if err := replica.ReplayEdits(strings.NewReader(journal.String())); err != nil {
+				t.Fatal(err)
+			}
+			got, want := replica.RenderString(dijkstrapf.RenderOptions{}), g.RenderString(dijkstrapf.RenderOptions{})
+			if replica.Fingerprint() != g.Fingerprint() || got != want {
+				t.Fatalf("replayed journal\n%s\ngives\n%s\nwant\n%s", journal.String(), got, want)
+			}
+			// Keys and their locks are left out of the fingerprint.
+			gotPath, gotKeys, gotErr := replica.FindPathKeys()
+			wantPath, wantKeys, wantErr := g.FindPathKeys()
+			if gotPath.Cost != wantPath.Cost || !reflect.DeepEqual(gotKeys, wantKeys) || !errors.Is(gotErr, wantErr) {
+				t.Fatalf("replayed journal\n%s\nfinds cost %g keys %v, want cost %g keys %v", journal.String(), gotPath.Cost, gotKeys, wantPath.Cost, wantKeys)
+			}
+		})
+	}
+}
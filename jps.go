@@ -0,0 +1,288 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnsupported is returned when a solver cannot handle the graph or
+// options it was given.
+var ErrUnsupported = errors.New("dijkstrapf: unsupported by this solver")
+
+// errForwardOnly is returned by the solvers that cannot search from the goal.
+var errForwardOnly = fmt.Errorf("%w: reverse search needs dijkstra", ErrUnsupported)
+
+// FindPathJPS runs jump point search. JPS only applies to grids where every
+// walkable cell has the same weight and no cost function is set; otherwise
+// it returns ErrUnsupported.
+func (g *Graph) FindPathJPS(opts ...Option) (Path, error) {
+	return g.run("jps", jps, opts)
+}
+
+// uniformWeight returns the weight shared by every walkable cell. Doors
+// that are not open make the weights uneven.
+func (g *Graph) uniformWeight() (float64, bool) {
+	if g.shutDoors() {
+		return 0, false
+	}
+	w := math.NaN()
+	for y, row := range g.weights {
+		for x, cw := range row {
+			if g.gridMatrix[y][x] == Wall {
+				continue
+			}
+			if math.IsNaN(w) {
+				w = cw
+			} else if cw != w {
+				return 0, false
+			}
+		}
+	}
+	return w, true
+}
+
+func (g *Graph) walkable(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.width && y < g.height && g.gridMatrix[y][x] != Wall
+}
+
+func jps(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	w, ok := g.uniformWeight()
+	if !ok || o.Cost != nil || o.Clearance > 0 || o.RiskPenalty > 0 {
+		return Path{}, fmt.Errorf("%w: jps needs uniform cell weights", ErrUnsupported)
+	}
+	if g.diagonal && g.corners != CornerAlways {
+		return Path{}, fmt.Errorf("%w: jps needs corner rule %v", ErrUnsupported, CornerAlways)
+	}
+	if len(g.extra) > 0 || len(g.removed) > 0 {
+		return Path{}, fmt.Errorf("%w: jps cannot follow added or removed edges", ErrUnsupported)
+	}
+	if g.wrap {
+		return Path{}, fmt.Errorf("%w: jps on wrapping grids", ErrUnsupported)
+	}
+	j := &jumper{g: g, goal: g.goal}
+	h := g.heuristic(o)
+	span := func(a, b Point) float64 {
+		return w * g.gridDistance(abs(a.X-b.X), abs(a.Y-b.Y))
+	}
+
+	n := g.nodeCount()
+	dist, prev := g.resetSearch()
+	queued := make([]bool, n)
+	dist[src] = 0
+	pq := o.newQueue(n)
+	pq.Push(src, h(g.start, g.goal))
+	queued[src] = true
+	for pq.Len() > 0 {
+		cur, _ := pq.PopMin()
+		queued[cur] = false
+		g.settle(o, cur, dist[cur])
+		if cur == dst {
+			break
+		}
+		if err := g.cancelled(o); err != nil {
+			return g.jumpPartial(o, dist, prev, err)
+		}
+		p := g.point(cur)
+		parent := Point{-1, -1}
+		if prev[cur] != -1 {
+			parent = g.point(prev[cur])
+		}
+		for _, nb := range j.successors(p, parent) {
+			id := g.id(nb)
+			nd := dist[cur] + span(p, nb)
+			if nd >= dist[id] {
+				continue
+			}
+			g.relax(o, id, dist[id], nd)
+			dist[id] = nd
+			prev[id] = cur
+			f := nd + h(nb, g.goal)
+			if queued[id] {
+				pq.DecreaseKey(id, f)
+			} else {
+				pq.Push(id, f)
+				queued[id] = true
+			}
+		}
+	}
+
+	g.finish(o, dst, dist[dst])
+	if math.IsInf(dist[dst], 1) {
+		return g.jumpPartial(o, dist, prev, ErrNoPath)
+	}
+	return Path{Points: expandJumps(g.reconstruct(prev, dst)), Cost: dist[dst]}, nil
+}
+
+// jumpPartial is partial for a search tree of jump points.
+func (g *Graph) jumpPartial(o *Options, dist []float64, prev []int, cause error) (Path, error) {
+	path, err := g.partial(o, dist, prev, cause)
+	path.Points = expandJumps(path.Points)
+	return path, err
+}
+
+// expandJumps fills in the straight or diagonal runs between jump points.
+func expandJumps(jumps []Point) []Point {
+	if len(jumps) == 0 {
+		return nil
+	}
+	out := []Point{jumps[0]}
+	for i := 1; i < len(jumps); i++ {
+		a, b := jumps[i-1], jumps[i]
+		dx, dy := sign(b.X-a.X), sign(b.Y-a.Y)
+		for a != b {
+			a = Point{a.X + dx, a.Y + dy}
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+type jumper struct {
+	g    *Graph
+	goal Point
+}
+
+// successors returns the jump points reachable from p, which was reached
+// from parent (or is the start when parent is off the grid).
+func (j *jumper) successors(p, parent Point) []Point {
+	var out []Point
+	for _, d := range j.pruned(p, parent) {
+		if q, ok := j.jump(p, d); ok {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// pruned returns the directions worth exploring from p.
+func (j *jumper) pruned(p, parent Point) []Point {
+	g := j.g
+	if parent.X < 0 {
+		dirs := append([]Point(nil), orthogonal...)
+		if g.diagonal {
+			dirs = append(dirs, diagonals...)
+		}
+		return dirs
+	}
+	dx, dy := sign(p.X-parent.X), sign(p.Y-parent.Y)
+	x, y := p.X, p.Y
+	var dirs []Point
+	if !g.diagonal {
+		if dx != 0 {
+			dirs = append(dirs, Point{0, -1}, Point{0, 1}, Point{dx, 0})
+		} else {
+			dirs = append(dirs, Point{-1, 0}, Point{1, 0}, Point{0, dy})
+		}
+		return dirs
+	}
+	switch {
+	case dx != 0 && dy != 0:
+		dirs = append(dirs, Point{0, dy}, Point{dx, 0}, Point{dx, dy})
+		if !g.walkable(x-dx, y) {
+			dirs = append(dirs, Point{-dx, dy})
+		}
+		if !g.walkable(x, y-dy) {
+			dirs = append(dirs, Point{dx, -dy})
+		}
+	case dx != 0:
+		dirs = append(dirs, Point{dx, 0})
+		if !g.walkable(x, y+1) {
+			dirs = append(dirs, Point{dx, 1})
+		}
+		if !g.walkable(x, y-1) {
+			dirs = append(dirs, Point{dx, -1})
+		}
+	default:
+		dirs = append(dirs, Point{0, dy})
+		if !g.walkable(x+1, y) {
+			dirs = append(dirs, Point{1, dy})
+		}
+		if !g.walkable(x-1, y) {
+			dirs = append(dirs, Point{-1, dy})
+		}
+	}
+	return dirs
+}
+
+// jump walks from p in direction d until it hits a wall, the goal or a cell
+// with a forced neighbour.
+func (j *jumper) jump(p, d Point) (Point, bool) {
+	g := j.g
+	dx, dy := d.X, d.Y
+	x, y := p.X, p.Y
+	for {
+		x, y = x+dx, y+dy
+		if !g.walkable(x, y) {
+			return Point{}, false
+		}
+		cur := Point{x, y}
+		if cur == j.goal {
+			return cur, true
+		}
+		if g.diagonal {
+			switch {
+			case dx != 0 && dy != 0:
+				if (g.walkable(x-dx, y+dy) && !g.walkable(x-dx, y)) ||
+					(g.walkable(x+dx, y-dy) && !g.walkable(x, y-dy)) {
+					return cur, true
+				}
+				if _, ok := j.jump(cur, Point{dx, 0}); ok {
+					return cur, true
+				}
+				if _, ok := j.jump(cur, Point{0, dy}); ok {
+					return cur, true
+				}
+			case dx != 0:
+				if (g.walkable(x+dx, y+1) && !g.walkable(x, y+1)) ||
+					(g.walkable(x+dx, y-1) && !g.walkable(x, y-1)) {
+					return cur, true
+				}
+			default:
+				if (g.walkable(x+1, y+dy) && !g.walkable(x+1, y)) ||
+					(g.walkable(x-1, y+dy) && !g.walkable(x-1, y)) {
+					return cur, true
+				}
+			}
+			continue
+		}
+		if dx != 0 {
+			if (g.walkable(x, y-1) && !g.walkable(x-dx, y-1)) ||
+				(g.walkable(x, y+1) && !g.walkable(x-dx, y+1)) {
+				return cur, true
+			}
+			continue
+		}
+		if (g.walkable(x-1, y) && !g.walkable(x-1, y-dy)) ||
+			(g.walkable(x+1, y) && !g.walkable(x+1, y-dy)) {
+			return cur, true
+		}
+		if _, ok := j.jump(cur, Point{1, 0}); ok {
+			return cur, true
+		}
+		if _, ok := j.jump(cur, Point{-1, 0}); ok {
+			return cur, true
+		}
+	}
+}
+
+func init() {
+	Register("jps", jps)
+}
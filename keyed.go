@@ -0,0 +1,188 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNodeExists is returned by KeyedNetwork.AddNode for keys already in
+// use.
+var ErrNodeExists = errors.New("dijkstrapf: node already exists")
+
+// KeyedNetwork is a Network whose nodes are identified by keys of any
+// comparable type, such as the names of the locations they model, and can
+// carry metadata.
+type KeyedNetwork[K comparable] struct {
+	net  Network
+	ids  map[K]int
+	keys []K
+	meta []map[string]any
+}
+
+// KeyedEdge is an edge out of a node of a KeyedNetwork.
+type KeyedEdge[K comparable] struct {
+	To   K
+	Cost float64
+}
+
+// KeyedPath is a sequence of node keys from the source to the target,
+// inclusive, with the total cost of its edges.
+type KeyedPath[K comparable] struct {
+	Nodes []K
+	Cost  float64
+}
+
+// NewKeyedNetwork returns an empty network keyed by K.
+func NewKeyedNetwork[K comparable]() *KeyedNetwork[K] {
+	return &KeyedNetwork[K]{ids: make(map[K]int)}
+}
+
+// AddNode adds a node under key with the given metadata, which may be nil.
+// A key that was removed can be added again.
+func (n *KeyedNetwork[K]) AddNode(key K, meta map[string]any) error {
+	id, ok := n.ids[key]
+	switch {
+	case ok && n.net.HasNode(id):
+		return fmt.Errorf("%w: %v", ErrNodeExists, key)
+	case ok:
+		n.net.RestoreNode(id)
+	default:
+		id = n.net.AddNode()
+		n.ids[key] = id
+		n.keys = append(n.keys, key)
+		n.meta = append(n.meta, nil)
+	}
+	n.meta[id] = meta
+	return nil
+}
+
+// id returns the id of key's node.
+func (n *KeyedNetwork[K]) id(key K) (int, error) {
+	id, ok := n.ids[key]
+	if !ok || !n.net.HasNode(id) {
+		return 0, fmt.Errorf("%w: %v", ErrNoNode, key)
+	}
+	return id, nil
+}
+
+// HasNode reports whether key names a node of the network.
+func (n *KeyedNetwork[K]) HasNode(key K) bool {
+	_, err := n.id(key)
+	return err == nil
+}
+
+// Keys returns the keys of the nodes in the order they were first added.
+func (n *KeyedNetwork[K]) Keys() []K {
+	var out []K
+	for id, key := range n.keys {
+		if n.net.HasNode(id) {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// Metadata returns the metadata of key's node.
+func (n *KeyedNetwork[K]) Metadata(key K) (map[string]any, bool) {
+	id, err := n.id(key)
+	if err != nil {
+		return nil, false
+	}
+	return n.meta[id], true
+}
+
+// SetMetadata replaces the metadata of key's node.
+func (n *KeyedNetwork[K]) SetMetadata(key K, meta map[string]any) error {
+	id, err := n.id(key)
+	if err != nil {
+		return err
+	}
+	n.meta[id] = meta
+	return nil
+}
+
+// RemoveNode takes key's node out of the network with all its edges. Its
+// metadata is dropped.
+func (n *KeyedNetwork[K]) RemoveNode(key K) error {
+	id, err := n.id(key)
+	if err != nil {
+		return err
+	}
+	n.meta[id] = nil
+	return n.net.RemoveNode(id)
+}
+
+// AddEdge adds an edge between the nodes of two keys, or changes its cost.
+func (n *KeyedNetwork[K]) AddEdge(from, to K, cost float64) error {
+	a, err := n.id(from)
+	if err != nil {
+		return err
+	}
+	b, err := n.id(to)
+	if err != nil {
+		return err
+	}
+	return n.net.AddEdge(a, b, cost)
+}
+
+// RemoveEdge removes the edge between the nodes of two keys.
+func (n *KeyedNetwork[K]) RemoveEdge(from, to K) error {
+	a, err := n.id(from)
+	if err != nil {
+		return err
+	}
+	b, err := n.id(to)
+	if err != nil {
+		return err
+	}
+	if err := n.net.RemoveEdge(a, b); err != nil {
+		return fmt.Errorf("%w from %v to %v", ErrNoEdge, from, to)
+	}
+	return nil
+}
+
+// Edge returns the cost of the edge between the nodes of two keys, if
+// there is one.
+func (n *KeyedNetwork[K]) Edge(from, to K) (float64, bool) {
+	a, errA := n.id(from)
+	b, errB := n.id(to)
+	if errA != nil || errB != nil {
+		return 0, false
+	}
+	return n.net.Edge(a, b)
+}
+
+// EdgesFrom returns the edges leaving key's node.
+func (n *KeyedNetwork[K]) EdgesFrom(key K) []KeyedEdge[K] {
+	id, err := n.id(key)
+	if err != nil {
+		return nil
+	}
+	var out []KeyedEdge[K]
+	for _, e := range n.net.EdgesFrom(id) {
+		out = append(out, KeyedEdge[K]{n.keys[e.To], e.Cost})
+	}
+	return out
+}
+
+// ShortestPath runs Dijkstra's algorithm between the nodes of two keys, as
+// Network.ShortestPath does.
+func (n *KeyedNetwork[K]) ShortestPath(from, to K, opts ...Option) (KeyedPath[K], error) {
+	a, err := n.id(from)
+	if err != nil {
+		return KeyedPath[K]{}, err
+	}
+	b, err := n.id(to)
+	if err != nil {
+		return KeyedPath[K]{}, err
+	}
+	p, err := n.net.ShortestPath(a, b, opts...)
+	if err != nil {
+		return KeyedPath[K]{}, err
+	}
+	keys := make([]K, len(p.Nodes))
+	for i, id := range p.Nodes {
+		keys[i] = n.keys[id]
+	}
+	return KeyedPath[K]{Nodes: keys, Cost: p.Cost}, nil
+}
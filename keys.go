@@ -0,0 +1,202 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrTooManyColors is returned by PlaceKey and PlaceLock once a graph uses
+// more key colors than the key search can track.
+var ErrTooManyColors = errors.New("dijkstrapf: too many key colors")
+
+// maxKeyColors is the number of key colors a graph may use.
+const maxKeyColors = 64
+
+// PlaceKey puts a key of the given color on p. Walking onto p picks it up.
+func (g *Graph) PlaceKey(p Point, color string) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	c, err := g.keyColor(color)
+	if err != nil {
+		return err
+	}
+	if g.keys == nil {
+		g.keys = make(map[int]int)
+	}
+	g.keys[g.id(p)] = c
+	g.changed(ChangeKey, p)
+	return nil
+}
+
+// PlaceLock puts a locked door of the given color on p. To every solver
+// but FindPathKeys it is a locked door like any other; FindPathKeys walks
+// through it once it holds a key of its color. OpenDoor unlocks it for
+// good.
+func (g *Graph) PlaceLock(p Point, color string) error {
+	c, err := g.keyColor(color)
+	if err != nil {
+		return err
+	}
+	if err := g.SetDoor(p, DoorLocked, 0); err != nil {
+		return err
+	}
+	if g.locks == nil {
+		g.locks = make(map[int]int)
+	}
+	g.locks[g.id(p)] = c
+	// SetDoor reported a plain locked door; report its color too.
+	g.changed(ChangeDoor, p)
+	return nil
+}
+
+// keyColor returns the index of color, adding it if it is new.
+func (g *Graph) keyColor(color string) (int, error) {
+	for i, c := range g.keyColors {
+		if c == color {
+			return i, nil
+		}
+	}
+	if len(g.keyColors) == maxKeyColors {
+		return 0, ErrTooManyColors
+	}
+	g.keyColors = append(g.keyColors, color)
+	return len(g.keyColors) - 1, nil
+}
+
+// keyState is a node of the key search: a cell and the colors held.
+type keyState struct {
+	node int
+	keys uint64
+}
+
+// FindPathKeys finds the cheapest path from the start to the goal on a
+// map with colored keys and locks. The search runs over pairs of a cell
+// and the set of key colors held, so it finds detours to fetch a key when
+// a lock is in the way, and fetches only the keys it needs. Keys are never
+// used up. It returns the path and, in order, the cells where it picks up
+// a key of a color it did not hold yet.
+func (g *Graph) FindPathKeys(opts ...Option) (Path, []Point, error) {
+	var picked []Point
+	path, err := g.run("keys", func(g *Graph, o *Options) (Path, error) {
+		if o.Reverse {
+			return Path{}, errForwardOnly
+		}
+		src, dst, err := g.endpoints()
+		if err != nil {
+			return Path{}, err
+		}
+		adj := g.unlockedAdjacency()
+		ids := make(map[keyState]int)
+		var states []keyState
+		var dist []float64
+		var prev []int
+		state := func(s keyState) int {
+			if id, ok := ids[s]; ok {
+				return id
+			}
+			ids[s] = len(states)
+			states = append(states, s)
+			dist = append(dist, math.Inf(1))
+			prev = append(prev, -1)
+			return len(states) - 1
+		}
+		start := state(keyState{src, g.keysAt(src, 0)})
+		dist[start] = 0
+		pq := NewBinaryHeap(0)
+		pq.Push(start, 0)
+		goal := -1
+		for pq.Len() > 0 {
+			cur, d := pq.PopMin()
+			s := states[cur]
+			g.settle(o, s.node, d)
+			if s.node == dst {
+				goal = cur
+				break
+			}
+			if err := g.cancelled(o); err != nil {
+				return Path{}, err
+			}
+			for _, e := range adj[s.node] {
+				if !g.mayEnter(e.to, s.keys) {
+					continue
+				}
+				c, err := g.stepCost(o, s.node, e)
+				if err != nil {
+					return Path{}, err
+				}
+				next := state(keyState{e.to, g.keysAt(e.to, s.keys)})
+				if d+c >= dist[next] {
+					continue
+				}
+				fresh := math.IsInf(dist[next], 1)
+				dist[next], prev[next] = d+c, cur
+				if fresh {
+					pq.Push(next, d+c)
+				} else {
+					pq.DecreaseKey(next, d+c)
+				}
+			}
+		}
+		if goal < 0 {
+			g.finish(o, dst, math.Inf(1))
+			return Path{}, ErrNoPath
+		}
+		g.finish(o, dst, dist[goal])
+
+		var trail []keyState
+		for id := goal; id >= 0; id = prev[id] {
+			trail = append(trail, states[id])
+		}
+		path := Path{Cost: dist[goal]}
+		for i := len(trail) - 1; i >= 0; i-- {
+			s := trail[i]
+			path.Points = append(path.Points, g.point(s.node))
+			if i == len(trail)-1 && s.keys != 0 || i < len(trail)-1 && s.keys != trail[i+1].keys {
+				picked = append(picked, g.point(s.node))
+			}
+		}
+		return path, nil
+	}, opts)
+	return path, picked, err
+}
+
+// keysAt returns the colors held after stepping onto node with held.
+func (g *Graph) keysAt(node int, held uint64) uint64 {
+	if c, ok := g.keys[node]; ok {
+		held |= 1 << c
+	}
+	return held
+}
+
+// mayEnter reports whether a walker holding the colors in held may enter
+// node: anything but a colored lock, or one whose key it holds.
+func (g *Graph) mayEnter(node int, held uint64) bool {
+	c, ok := g.locks[node]
+	if !ok || g.doors[node].state != DoorLocked {
+		return true
+	}
+	return held&(1<<c) != 0
+}
+
+// unlockedAdjacency returns the adjacency list with every colored lock
+// still locked treated as a closed door, leaving it to the key search to
+// decide who may pass.
+func (g *Graph) unlockedAdjacency() [][]edge {
+	if len(g.locks) == 0 {
+		return g.adjacency()
+	}
+	saved := g.doors
+	g.doors = make(map[int]door, len(saved))
+	for node, d := range saved {
+		if _, ok := g.locks[node]; ok && d.state == DoorLocked {
+			d.state = DoorClosed
+		}
+		g.doors[node] = d
+	}
+	g.buildAdjacency()
+	adj := g.adjList
+	g.doors = saved
+	g.stale = true
+	return adj
+}
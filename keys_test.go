@@ -0,0 +1,119 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestFindPathKeys(t *testing.T) {
+	type item struct {
+		at    dijkstrapf.Point
+		color string
+	}
+	p := func(x, y int) dijkstrapf.Point { return dijkstrapf.Point{X: x, Y: y} }
+	tests := []struct {
+		name        string
+		grid        string
+		keys, locks []item
+		cost        float64
+		picked      []dijkstrapf.Point
+		err         error
+	}{
+		{
+			name: "no locks",
+			grid: "S..#G\n##.#.\n.....\n",
+			keys: []item{{p(0, 2), "red"}},
+			cost: 8,
+		},
+		{
+			name:   "key opens lock",
+			grid:   "S..#G\n##.#.\n.....\n",
+			keys:   []item{{p(0, 2), "red"}},
+			locks:  []item{{p(3, 2), "red"}},
+			cost:   12,
+			picked: []dijkstrapf.Point{p(0, 2)},
+		},
+		{
+			name:  "key out of reach",
+			grid:  "S..#G\n##.#.\n.#...\n",
+			keys:  []item{{p(0, 2), "red"}},
+			locks: []item{{p(3, 2), "red"}},
+			err:   dijkstrapf.ErrNoPath,
+		},
+		{
+			name:   "several colors",
+			grid:   "S..#G\n##.#.\n.....\n",
+			keys:   []item{{p(1, 2), "blue"}, {p(0, 2), "red"}, {p(2, 0), "green"}},
+			locks:  []item{{p(3, 2), "red"}, {p(4, 1), "blue"}},
+			cost:   12,
+			picked: []dijkstrapf.Point{p(2, 0), p(1, 2), p(0, 2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tt.grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, k := range tt.keys {
+				if err := g.PlaceKey(k.at, k.color); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, l := range tt.locks {
+				if err := g.PlaceLock(l.at, l.color); err != nil {
+					t.Fatal(err)
+				}
+			}
+			path, picked, err := g.FindPathKeys()
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path.Cost != tt.cost || !reflect.DeepEqual(picked, tt.picked) {
+				t.Fatalf("cost %g picking up %v, want %g picking up %v", path.Cost, picked, tt.cost, tt.picked)
+			}
+			if len(tt.locks) == 0 {
+				want, err := g.FindPath()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if path.Cost != want.Cost {
+					t.Fatalf("cost %g, FindPath gives %g", path.Cost, want.Cost)
+				}
+				if err := g.ValidatePath(path); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceKeyNotifies(t *testing.T) {
+	g, err := dijkstrapf.LoadGrid(strings.NewReader("S..G\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := g.Fingerprint()
+	var got []dijkstrapf.Change
+	g.OnChange(func(c dijkstrapf.Change) { got = append(got, c) })
+	if err := g.PlaceKey(dijkstrapf.Point{X: 1}, "red"); err != nil {
+		t.Fatal(err)
+	}
+	want := []dijkstrapf.Change{{Kind: dijkstrapf.ChangeKey, At: dijkstrapf.Point{X: 1}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes %v, want %v", got, want)
+	}
+	if g.Fingerprint() != before {
+		t.Fatal("a key changed the fingerprint")
+	}
+}
@@ -0,0 +1,190 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// The maze solvers below only look at the cell they stand on, as a simple
+// robot would. They move between orthogonal or diagonal grid neighbours
+// and ignore one-way extra edges. Neither returns shortest paths; they are
+// baselines to compare the other solvers against.
+
+// FindPathWallFollower walks from the start keeping its left hand on the
+// wall, using orthogonal moves only, and returns the whole walk, dead ends
+// included. This always works in a maze whose walls are all connected, but
+// can circle an island forever otherwise; in that case, and when the goal
+// cannot be reached, it returns an error wrapping ErrUnsupported.
+func (g *Graph) FindPathWallFollower(opts ...Option) (Path, error) {
+	return g.run("wallfollower", wallFollower, opts)
+}
+
+// FindPathTremaux solves the maze with Trémaux's algorithm: it marks every
+// passage it walks, never takes one marked twice and turns back when it
+// reaches a junction it has already visited. It always finds the goal if
+// it can be reached and returns the route formed by the passages marked
+// once, which is free of dead ends but not necessarily the shortest.
+func (g *Graph) FindPathTremaux(opts ...Option) (Path, error) {
+	return g.run("tremaux", tremaux, opts)
+}
+
+// localMove returns the edge from cur to the cell one step d away, if the
+// step can be walked both ways.
+func (g *Graph) localMove(adj [][]edge, cur int, d Point) (edge, bool) {
+	p := g.point(cur)
+	q := Point{p.X + d.X, p.Y + d.Y}
+	if g.wrap {
+		q = Point{(q.X + g.width) % g.width, (q.Y + g.height) % g.height}
+	}
+	if !g.InBounds(q) {
+		return edge{}, false
+	}
+	e, ok := findEdge(adj[cur], g.id(q))
+	if !ok {
+		return edge{}, false
+	}
+	_, back := findEdge(adj[e.to], cur)
+	return e, back
+}
+
+func wallFollower(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+	g.resetSearch()
+
+	// heading indexes orthogonal, which runs clockwise from up; seen
+	// records every cell and heading the walker has been in.
+	seen := make([]bool, 4*g.nodeCount())
+	cur, heading := src, 0
+	walk := []Point{g.start}
+	cost := 0.0
+	for cur != dst {
+		if seen[4*cur+heading] {
+			return Path{}, fmt.Errorf("%w: wall follower went round in a loop", ErrUnsupported)
+		}
+		seen[4*cur+heading] = true
+		g.settle(o, cur, cost)
+		moved := false
+		// Try left, straight on, right and back.
+		for _, turn := range []int{3, 0, 1, 2} {
+			h := (heading + turn) % 4
+			e, ok := g.localMove(adj, cur, orthogonal[h])
+			if !ok {
+				continue
+			}
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return Path{}, err
+			}
+			if math.IsInf(c, 1) {
+				continue
+			}
+			cur, heading, cost = e.to, h, cost+c
+			walk = append(walk, g.point(cur))
+			moved = true
+			break
+		}
+		if !moved {
+			return Path{}, fmt.Errorf("%w: wall follower has no orthogonal move", ErrUnsupported)
+		}
+	}
+	g.finish(o, dst, cost)
+	return Path{Points: walk, Cost: cost}, nil
+}
+
+func tremaux(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+	g.resetSearch()
+	dirs := orthogonal
+	if g.diagonal {
+		dirs = kingMoves
+	}
+
+	// marks counts how often each passage was walked, keyed by its cells in
+	// ascending order. route holds the passages marked once: the way back
+	// to the start.
+	marks := make(map[[2]int]int)
+	passage := func(a, b int) [2]int {
+		if a > b {
+			a, b = b, a
+		}
+		return [2]int{a, b}
+	}
+	visited := make([]bool, g.nodeCount())
+	route := []int{src}
+	cost := []float64{0}
+	cur, from := src, -1
+	for cur != dst {
+		g.settle(o, cur, cost[len(cost)-1])
+		old := visited[cur]
+		visited[cur] = true
+
+		next, least := -1, 3
+		var step float64
+		if old && from >= 0 && marks[passage(from, cur)] == 1 {
+			// Back at a known junction through a new passage: turn back.
+			next = from
+			e, _ := findEdge(adj[cur], from)
+			if step, err = g.stepCost(o, cur, e); err != nil {
+				return Path{}, err
+			}
+		} else {
+			for _, d := range dirs {
+				e, ok := g.localMove(adj, cur, d)
+				if !ok {
+					continue
+				}
+				m := marks[passage(cur, e.to)]
+				if m >= least || m >= 2 {
+					continue
+				}
+				c, err := g.stepCost(o, cur, e)
+				if err != nil {
+					return Path{}, err
+				}
+				if !math.IsInf(c, 1) {
+					next, least, step = e.to, m, c
+				}
+			}
+		}
+		if next < 0 {
+			g.finish(o, dst, math.Inf(1))
+			return Path{}, ErrNoPath
+		}
+
+		marks[passage(cur, next)]++
+		if n := len(route); n >= 2 && route[n-2] == next {
+			route, cost = route[:n-1], cost[:n-1]
+		} else {
+			route = append(route, next)
+			cost = append(cost, cost[len(cost)-1]+step)
+		}
+		from, cur = cur, next
+	}
+
+	total := cost[len(cost)-1]
+	g.finish(o, dst, total)
+	points := make([]Point, len(route))
+	for i, id := range route {
+		points[i] = g.point(id)
+	}
+	return Path{Points: points, Cost: total}, nil
+}
+
+func init() {
+	Register("wallfollower", wallFollower)
+	Register("tremaux", tremaux)
+}
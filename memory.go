@@ -0,0 +1,84 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMemoryLimit is returned when a solve would need more memory than
+// WithMemoryLimit allows.
+var ErrMemoryLimit = errors.New("dijkstrapf: memory limit exceeded")
+
+// Approximate bytes a solve tracks per cell: the distance, predecessor and
+// closed arrays plus a queue slot, and one adjacency list entry per edge.
+const (
+	searchBytesPerNode = 8 + 8 + 1 + 24
+	listBytesPerNode   = 24
+	bytesPerEdge       = 16
+)
+
+// WithMemoryLimit caps the memory a solve may use at roughly bytes. The
+// estimate counts the per-cell state the solvers keep for every cell of
+// the grid, so it is known before the search starts; a solve that would
+// exceed it fails with ErrMemoryLimit up front instead of exhausting the
+// host's memory. Solvers run through Solve or the FindPath methods
+// honour it.
+func WithMemoryLimit(bytes int64) Option {
+	return func(o *Options) { o.MemoryLimit = bytes }
+}
+
+// fallbackExpansions is how many expansions per cell of the grid the
+// memory fallback may make before giving up.
+const fallbackExpansions = 100
+
+// errOverBudget stops a fallback IDA* solve that used up its expansions.
+var errOverBudget = errors.New("dijkstrapf: expansion budget used up")
+
+// WithMemoryFallback makes a solve that exceeds WithMemoryLimit run
+// FindPathIDAStar instead, which needs memory only for the path it is
+// exploring. IDA* may re-expand cells very many times on open or weighted
+// grids, so the fallback gives up with the ErrMemoryLimit error after
+// expanding a hundred times as many cells as the grid has. Use
+// WithContext to bound its time more tightly.
+func WithMemoryFallback() Option {
+	return func(o *Options) { o.MemoryFallback = true }
+}
+
+// idastarFallback runs IDA* within the budget of the memory fallback in
+// place of a solve that failed the memory check with cause.
+func idastarFallback(cause error) Solver {
+	return func(g *Graph, o *Options) (Path, error) {
+		o.expansionBudget = fallbackExpansions * g.nodeCount()
+		path, err := idastar(g, o)
+		if errors.Is(err, errOverBudget) {
+			return Path{}, cause
+		}
+		return path, err
+	}
+}
+
+// searchBytes estimates the memory a solve on g needs.
+func (g *Graph) searchBytes() int64 {
+	n := int64(g.nodeCount())
+	b := n * searchBytesPerNode
+	if g.stale || g.adjList == nil {
+		degree := int64(len(orthogonal))
+		if g.diagonal {
+			degree += int64(len(diagonals))
+		}
+		b += n * (listBytesPerNode + degree*bytesPerEdge)
+	}
+	return b
+}
+
+// checkMemory returns an error wrapping ErrMemoryLimit if a solve on g
+// would exceed the limit of o.
+func (g *Graph) checkMemory(o *Options) error {
+	if o.MemoryLimit <= 0 {
+		return nil
+	}
+	if need := g.searchBytes(); need > o.MemoryLimit {
+		return fmt.Errorf("%w: need about %d bytes, limit is %d", ErrMemoryLimit, need, o.MemoryLimit)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Metric selects a per-cell measure of Metrics for drawing.
+type Metric int
+
+const (
+	// MetricEccentricity is the cost of reaching the farthest reachable
+	// cell.
+	MetricEccentricity Metric = iota
+	// MetricCloseness is closeness centrality.
+	MetricCloseness
+)
+
+var metricNames = []string{"eccentricity", "closeness"}
+
+func (m Metric) String() string {
+	if m >= 0 && int(m) < len(metricNames) {
+		return metricNames[m]
+	}
+	return fmt.Sprintf("Metric(%d)", int(m))
+}
+
+// Metrics describes where the walkable cells of a grid lie relative to
+// each other, such as where the centre of a map is. It is computed once by
+// Graph.Metrics and does not follow later edits of the graph.
+type Metrics struct {
+	width, height int
+	walkable      []bool
+	ecc           []float64
+	closeness     []float64
+	reach         []int
+	diameter      float64
+}
+
+// Metrics runs a search from every walkable cell, pricing steps with opts,
+// and measures each cell against all the cells it can reach. It takes time
+// quadratic in the number of cells, so it is meant for level analysis
+// rather than for every frame. It leaves the graph's last solve alone.
+func (g *Graph) Metrics(opts ...Option) (*Metrics, error) {
+	o := buildOptions(opts)
+	adj := g.adjacency()
+	n := g.nodeCount()
+	m := &Metrics{
+		width: g.width, height: g.height,
+		walkable: make([]bool, n), ecc: make([]float64, n),
+		closeness: make([]float64, n), reach: make([]int, n),
+	}
+	cells := 0
+	for id := range m.walkable {
+		p := g.point(id)
+		m.walkable[id] = !g.IsWall(p) && !math.IsInf(g.weights[p.Y][p.X], 1)
+		if m.walkable[id] {
+			cells++
+		}
+	}
+	dist := make([]float64, n)
+	for src, ok := range m.walkable {
+		if !ok {
+			continue
+		}
+		if o.Context != nil {
+			if err := o.Context.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if err := g.distancesFrom(o, adj, src, dist); err != nil {
+			return nil, err
+		}
+		sum, reached := 0.0, 0
+		for id, d := range dist {
+			if id == src || !m.walkable[id] || math.IsInf(d, 1) {
+				continue
+			}
+			sum += d
+			reached++
+			m.ecc[src] = max(m.ecc[src], d)
+		}
+		m.reach[src] = reached
+		if sum > 0 {
+			// Scaled by the share of cells reached, so that a cell of a
+			// small pocket does not look central.
+			m.closeness[src] = float64(reached) / sum * float64(reached) / float64(cells-1)
+		}
+		m.diameter = max(m.diameter, m.ecc[src])
+	}
+	return m, nil
+}
+
+// distancesFrom fills dist with the cost of the cheapest path from src to
+// every cell, without touching the graph's search state.
+func (g *Graph) distancesFrom(o *Options, adj [][]edge, src int, dist []float64) error {
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[src] = 0
+	pq := NewBinaryHeap(len(dist))
+	pq.Push(src, 0)
+	for pq.Len() > 0 {
+		cur, d := pq.PopMin()
+		for _, e := range adj[cur] {
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return err
+			}
+			nd := d + c
+			if nd >= dist[e.to] {
+				continue
+			}
+			if math.IsInf(dist[e.to], 1) {
+				pq.Push(e.to, nd)
+			} else {
+				pq.DecreaseKey(e.to, nd)
+			}
+			dist[e.to] = nd
+		}
+	}
+	return nil
+}
+
+func (m *Metrics) index(p Point) (int, bool) {
+	if p.X < 0 || p.Y < 0 || p.X >= m.width || p.Y >= m.height {
+		return 0, false
+	}
+	id := p.Y*m.width + p.X
+	return id, m.walkable[id]
+}
+
+// Eccentricity returns the cost of the cheapest path from p to the cell
+// reachable from p that is farthest from it, or +Inf if p is not walkable.
+func (m *Metrics) Eccentricity(p Point) float64 {
+	id, ok := m.index(p)
+	if !ok {
+		return math.Inf(1)
+	}
+	return m.ecc[id]
+}
+
+// Closeness returns the closeness centrality of p: the number of cells p
+// reaches over the total cost of reaching them, scaled by the share of the
+// grid's walkable cells it reaches. It is highest at the centre of the
+// map, and 0 for walls and cells that reach nothing.
+func (m *Metrics) Closeness(p Point) float64 {
+	id, ok := m.index(p)
+	if !ok {
+		return 0
+	}
+	return m.closeness[id]
+}
+
+// Diameter returns the greatest eccentricity of any cell: the cost of the
+// longest of all the shortest paths in the grid.
+func (m *Metrics) Diameter() float64 { return m.diameter }
+
+// Center returns the cells of least eccentricity among those that reach
+// the most cells, so that cells shut off in a small pocket do not count,
+// in grid order.
+func (m *Metrics) Center() []Point {
+	most := 0
+	for id, ok := range m.walkable {
+		if ok {
+			most = max(most, m.reach[id])
+		}
+	}
+	var center []Point
+	least := math.Inf(1)
+	for id, ok := range m.walkable {
+		if !ok || m.reach[id] != most {
+			continue
+		}
+		p := Point{id % m.width, id / m.width}
+		switch {
+		case m.ecc[id] < least:
+			least, center = m.ecc[id], []Point{p}
+		case m.ecc[id] == least:
+			center = append(center, p)
+		}
+	}
+	return center
+}
+
+// WriteHeatmap draws metric for every cell in the given style, scaled to
+// its largest value. Walls are drawn as '#'.
+func (m *Metrics) WriteHeatmap(w io.Writer, metric Metric, style HeatmapStyle) error {
+	values := m.ecc
+	if metric == MetricCloseness {
+		values = m.closeness
+	}
+	far := 0.0
+	for id, v := range values {
+		if m.walkable[id] {
+			far = max(far, v)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			id := y*m.width + x
+			if !m.walkable[id] {
+				bw.WriteByte(SymbolWall)
+				continue
+			}
+			writeHeat(bw, style, values[id], far)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
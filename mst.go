@@ -0,0 +1,75 @@
+package dijkstrapf
+
+import (
+	"math"
+	"sort"
+)
+
+// TreeEdge is an edge of a spanning tree, joining A and B.
+type TreeEdge struct {
+	A, B Point
+	Cost float64
+}
+
+// MST returns the edges of a minimum spanning tree of the walkable cells
+// and their total cost, for questions such as the cheapest set of
+// corridors that keeps every room connected. Edges are treated as two-way,
+// costing the cheaper of their two directions. If the walkable cells fall
+// apart into several regions the result spans each of them, a minimum
+// spanning forest. Edges come out in the order Kruskal's algorithm adds
+// them, cheapest first.
+func (g *Graph) MST() ([]TreeEdge, float64) {
+	type pair struct {
+		a, b int
+		cost float64
+	}
+	cheapest := make(map[[2]int]float64)
+	for from, edges := range g.adjacency() {
+		for _, e := range edges {
+			if e.to == from {
+				continue
+			}
+			k := [2]int{min(from, e.to), max(from, e.to)}
+			if c, ok := cheapest[k]; !ok || e.cost < c {
+				cheapest[k] = e.cost
+			}
+		}
+	}
+	pairs := make([]pair, 0, len(cheapest))
+	for k, c := range cheapest {
+		pairs = append(pairs, pair{k[0], k[1], c})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].cost != pairs[j].cost {
+			return pairs[i].cost < pairs[j].cost
+		}
+		if pairs[i].a != pairs[j].a {
+			return pairs[i].a < pairs[j].a
+		}
+		return pairs[i].b < pairs[j].b
+	})
+
+	root := make([]int, g.nodeCount())
+	for i := range root {
+		root[i] = i
+	}
+	find := func(v int) int {
+		for root[v] != v {
+			root[v] = root[root[v]]
+			v = root[v]
+		}
+		return v
+	}
+	var tree []TreeEdge
+	total := 0.0
+	for _, p := range pairs {
+		ra, rb := find(p.a), find(p.b)
+		if ra == rb || math.IsInf(p.cost, 1) {
+			continue
+		}
+		root[ra] = rb
+		tree = append(tree, TreeEdge{g.point(p.a), g.point(p.b), p.cost})
+		total += p.cost
+	}
+	return tree, total
+}
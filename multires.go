@@ -0,0 +1,72 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"math"
+)
+
+// FindPathMultiRes finds a path coarse to fine, which on large maps is
+// much quicker than searching the whole grid. It first solves a copy of
+// the grid downscaled by factor with ScaleMost, then runs A* on the grid
+// itself with the search kept to a corridor: the blocks of the coarse path
+// and the blocks next to them. The path is the shortest within the
+// corridor. On maps with few ways through, such as mazes, that is usually
+// the shortest overall; on open maps strewn with obstacles it may be some
+// way off. If the coarse grid has no path, or the corridor holds none, it
+// falls back to searching the whole grid, so it finds a path whenever
+// there is one.
+func (g *Graph) FindPathMultiRes(factor int, opts ...Option) (Path, error) {
+	if _, _, err := g.endpoints(); err != nil {
+		return Path{}, err
+	}
+	if factor > 0 && g.start.X/factor == g.goal.X/factor && g.start.Y/factor == g.goal.Y/factor {
+		// Both ends fall in one block, which the coarse grid cannot hold
+		// a start and goal apart in.
+		return g.FindPathAStar(opts...)
+	}
+	coarse, err := g.Downscale(factor, ScaleMost)
+	if err != nil {
+		return Path{}, err
+	}
+	o := buildOptions(opts)
+	var coarseOpts []Option
+	if o.Context != nil {
+		coarseOpts = append(coarseOpts, WithContext(o.Context))
+	}
+	route, err := coarse.FindPathAStar(coarseOpts...)
+	if errors.Is(err, ErrNoPath) {
+		return g.FindPathAStar(opts...)
+	}
+	if err != nil {
+		return Path{}, err
+	}
+
+	corridor := make([]bool, coarse.nodeCount())
+	for _, b := range route.Points {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if q := (Point{b.X + dx, b.Y + dy}); coarse.InBounds(q) {
+					corridor[coarse.id(q)] = true
+				}
+			}
+		}
+	}
+	base := o.Cost
+	cost := func(from, to Point) float64 {
+		switch {
+		case !corridor[coarse.id(Point{to.X / factor, to.Y / factor})]:
+			return math.Inf(1)
+		case base != nil:
+			return base(from, to)
+		}
+		return g.edgeCost(from, to)
+	}
+	// Keeping to the corridor only raises costs, so the heuristic of the
+	// whole grid stays admissible.
+	fine := append(opts[:len(opts):len(opts)], WithHeuristic(g.heuristic(o)), WithCost(cost))
+	path, err := g.FindPathAStar(fine...)
+	if errors.Is(err, ErrNoPath) {
+		return g.FindPathAStar(opts...)
+	}
+	return path, err
+}
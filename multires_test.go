@@ -0,0 +1,26 @@
+package dijkstrapf_test
+
+import (
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestMultiResSharedBlock(t *testing.T) {
+	g, err := dijkstrapf.LoadGrid(strings.NewReader("..G\n#.S\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.FindPathAStar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := g.FindPathMultiRes(2)
+	if err != nil {
+		t.Fatalf("FindPathMultiRes: %v", err)
+	}
+	if got.Cost != want.Cost {
+		t.Fatalf("cost %g, want %g", got.Cost, want.Cost)
+	}
+}
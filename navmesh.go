@@ -0,0 +1,243 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"math"
+)
+
+// Region is a rectangle of walkable cells that all have the same weight.
+type Region struct {
+	X, Y, Width, Height int
+	Weight              float64
+}
+
+// Contains reports whether p lies inside r.
+func (r Region) Contains(p Point) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
+
+// centre returns the cell in the middle of r.
+func (r Region) centre() Point {
+	return Point{r.X + (r.Width-1)/2, r.Y + (r.Height-1)/2}
+}
+
+// NavMesh covers the walkable cells of a grid with rectangular regions, so
+// that large open areas become a handful of nodes to search instead of a
+// cell each.
+type NavMesh struct {
+	Regions []Region
+	width   int
+	// regionOf holds the region index of every cell, or -1 for cells in
+	// none.
+	regionOf []int
+	// adj holds the indices of the regions a move leads to from each
+	// region.
+	adj [][]int
+}
+
+// RegionAt returns the index of the region containing p.
+func (m *NavMesh) RegionAt(p Point) (int, bool) {
+	if p.X < 0 || p.Y < 0 || p.X >= m.width || p.Y*m.width+p.X >= len(m.regionOf) {
+		return 0, false
+	}
+	i := m.regionOf[p.Y*m.width+p.X]
+	return i, i >= 0
+}
+
+// Neighbours returns the indices of the regions reachable from region i in
+// one move.
+func (m *NavMesh) Neighbours(i int) []int {
+	return append([]int(nil), m.adj[i]...)
+}
+
+// NavMesh returns the navigation mesh of the grid, building it if the grid
+// changed since it was last built. Regions are grown greedily in row-major
+// order, first as wide and then as tall as walkable cells of the same
+// weight allow. Impassable cells belong to no region. Two regions are
+// neighbours if a move of the grid, including added edges, leads from one
+// to the other.
+func (g *Graph) NavMesh() *NavMesh {
+	adj := g.adjacency()
+	if g.mesh != nil {
+		return g.mesh
+	}
+	m := &NavMesh{width: g.width, regionOf: make([]int, g.nodeCount())}
+	for i := range m.regionOf {
+		m.regionOf[i] = -1
+	}
+	free := func(x, y int, w float64) bool {
+		p := Point{x, y}
+		return g.InBounds(p) && m.regionOf[g.id(p)] < 0 && !g.IsWall(p) && g.weights[y][x] == w
+	}
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			w := g.weights[y][x]
+			if math.IsInf(w, 1) || !free(x, y, w) {
+				continue
+			}
+			width := 1
+			for free(x+width, y, w) {
+				width++
+			}
+			height := 1
+		grow:
+			for ; y+height < g.height; height++ {
+				for dx := 0; dx < width; dx++ {
+					if !free(x+dx, y+height, w) {
+						break grow
+					}
+				}
+			}
+			i := len(m.Regions)
+			m.Regions = append(m.Regions, Region{x, y, width, height, w})
+			for ry := y; ry < y+height; ry++ {
+				for rx := x; rx < x+width; rx++ {
+					m.regionOf[g.id(Point{rx, ry})] = i
+				}
+			}
+		}
+	}
+	m.adj = make([][]int, len(m.Regions))
+	for from, edges := range adj {
+		a := m.regionOf[from]
+		if a < 0 {
+			continue
+		}
+		for _, e := range edges {
+			b := m.regionOf[e.to]
+			if b >= 0 && b != a && !containsInt(m.adj[a], b) {
+				m.adj[a] = append(m.adj[a], b)
+			}
+		}
+	}
+	g.mesh = m
+	return m
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// FindPathNavMesh searches the regions of the navigation mesh for a chain
+// leading from the start to the goal, then runs A* over the cells of that
+// chain only. On maps with large open areas this settles far fewer cells
+// than a search of the whole grid, at the price of paths that need not be
+// the shortest. When no chain works out, for example under a cost function
+// that opens up impassable cells, it searches the whole grid instead.
+func (g *Graph) FindPathNavMesh(opts ...Option) (Path, error) {
+	return g.run("navmesh", navMesh, opts)
+}
+
+func navMesh(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	m := g.NavMesh()
+	corridor := g.regionChain(m, m.regionOf[src], m.regionOf[dst])
+	if corridor == nil {
+		return astar(g, o)
+	}
+
+	inside := make([]bool, len(m.Regions))
+	for _, i := range corridor {
+		inside[i] = true
+	}
+	adj := g.adjacency()
+	cost := o.Cost
+	restricted := *o
+	restricted.Heuristic = g.heuristic(o)
+	restricted.Cost = func(from, to Point) float64 {
+		if r := m.regionOf[g.id(to)]; r < 0 || !inside[r] {
+			return math.Inf(1)
+		}
+		if cost != nil {
+			return cost(from, to)
+		}
+		for _, e := range adj[g.id(from)] {
+			if e.to == g.id(to) {
+				return e.cost
+			}
+		}
+		return math.Inf(1)
+	}
+	restricted.Partial = false
+	path, err := astar(g, &restricted)
+	if errors.Is(err, ErrNoPath) {
+		return astar(g, o)
+	}
+	return path, err
+}
+
+// regionChain runs A* over the regions of m from region a to region b and
+// returns the regions on the way from a to b, or nil if either is missing or b cannot
+// be reached. Moving between regions costs the grid distance between their
+// centres at the mean of their weights.
+func (g *Graph) regionChain(m *NavMesh, a, b int) []int {
+	if a < 0 || b < 0 {
+		return nil
+	}
+	n := len(m.Regions)
+	dist := make([]float64, n)
+	prev := make([]int, n)
+	for i := range dist {
+		dist[i], prev[i] = math.Inf(1), -1
+	}
+	w := g.minWeight()
+	goal := m.Regions[b].centre()
+	h := func(i int) float64 {
+		dx, dy := g.delta(m.Regions[i].centre(), goal)
+		return w * g.gridDistance(dx, dy)
+	}
+	pq := NewBinaryHeap(n)
+	dist[a] = 0
+	pq.Push(a, h(a))
+	done := make([]bool, n)
+	for pq.Len() > 0 {
+		cur, _ := pq.PopMin()
+		done[cur] = true
+		if cur == b {
+			break
+		}
+		for _, next := range m.adj[cur] {
+			if done[next] {
+				continue
+			}
+			dx, dy := g.delta(m.Regions[cur].centre(), m.Regions[next].centre())
+			nd := dist[cur] + (m.Regions[cur].Weight+m.Regions[next].Weight)/2*g.gridDistance(dx, dy)
+			if nd >= dist[next] {
+				continue
+			}
+			fresh := math.IsInf(dist[next], 1)
+			dist[next], prev[next] = nd, cur
+			if fresh {
+				pq.Push(next, nd+h(next))
+			} else {
+				pq.DecreaseKey(next, nd+h(next))
+			}
+		}
+	}
+	if math.IsInf(dist[b], 1) {
+		return nil
+	}
+	var chain []int
+	for i := b; i != -1; i = prev[i] {
+		chain = append(chain, i)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+func init() {
+	Register("navmesh", navMesh)
+}
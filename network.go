@@ -0,0 +1,231 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoNode is returned for node ids a Network does not contain.
+var ErrNoNode = errors.New("dijkstrapf: no such node")
+
+// Network is a general directed graph with weighted edges, for problems
+// that are not grids, such as road or computer networks. Nodes are
+// identified by the ids AddNode hands out and can be removed and restored
+// while the network is in use.
+type Network struct {
+	nodes []netNode
+}
+
+type netNode struct {
+	present bool
+	// out and in hold the edges leaving and entering the node, in the
+	// order they were added; in[i].To is the source of the edge.
+	out, in []NetworkEdge
+}
+
+// NetworkEdge is an edge out of a node, or into it when listed by
+// EdgesTo.
+type NetworkEdge struct {
+	To   int
+	Cost float64
+}
+
+// NetworkPath is a sequence of node ids from the source to the target,
+// inclusive, with the total cost of its edges.
+type NetworkPath struct {
+	Nodes []int
+	Cost  float64
+}
+
+// NewNetwork returns an empty network.
+func NewNetwork() *Network { return &Network{} }
+
+// AddNode adds a node without edges and returns its id. Ids count up from
+// 0 and are never reused, even after RemoveNode.
+func (n *Network) AddNode() int {
+	n.nodes = append(n.nodes, netNode{present: true})
+	return len(n.nodes) - 1
+}
+
+// HasNode reports whether id is a node of the network.
+func (n *Network) HasNode(id int) bool {
+	return id >= 0 && id < len(n.nodes) && n.nodes[id].present
+}
+
+// Nodes returns the ids of the nodes in increasing order.
+func (n *Network) Nodes() []int {
+	var out []int
+	for id, node := range n.nodes {
+		if node.present {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// RemoveNode takes id out of the network together with every edge leaving
+// or entering it, as when a server goes offline.
+func (n *Network) RemoveNode(id int) error {
+	if !n.HasNode(id) {
+		return fmt.Errorf("%w: %d", ErrNoNode, id)
+	}
+	node := &n.nodes[id]
+	for _, e := range node.out {
+		n.nodes[e.To].in = dropEdge(n.nodes[e.To].in, id)
+	}
+	for _, e := range node.in {
+		n.nodes[e.To].out = dropEdge(n.nodes[e.To].out, id)
+	}
+	*node = netNode{}
+	return nil
+}
+
+// RestoreNode puts a removed node back under its old id, without the
+// edges it had.
+func (n *Network) RestoreNode(id int) error {
+	if id < 0 || id >= len(n.nodes) {
+		return fmt.Errorf("%w: %d", ErrNoNode, id)
+	}
+	n.nodes[id].present = true
+	return nil
+}
+
+// AddEdge adds an edge from one node to another, or changes its cost if
+// it exists. Add a second edge the other way for a two-way link.
+func (n *Network) AddEdge(from, to int, cost float64) error {
+	if !n.HasNode(from) {
+		return fmt.Errorf("%w: %d", ErrNoNode, from)
+	}
+	if !n.HasNode(to) {
+		return fmt.Errorf("%w: %d", ErrNoNode, to)
+	}
+	if !(cost >= 0) {
+		return ErrNegativeCost
+	}
+	src, dst := &n.nodes[from], &n.nodes[to]
+	for i := range src.out {
+		if src.out[i].To == to {
+			src.out[i].Cost = cost
+			for j := range dst.in {
+				if dst.in[j].To == from {
+					dst.in[j].Cost = cost
+				}
+			}
+			return nil
+		}
+	}
+	src.out = append(src.out, NetworkEdge{to, cost})
+	dst.in = append(dst.in, NetworkEdge{from, cost})
+	return nil
+}
+
+// RemoveEdge removes the edge from one node to another, as when a road
+// closes.
+func (n *Network) RemoveEdge(from, to int) error {
+	if _, ok := n.Edge(from, to); !ok {
+		return fmt.Errorf("%w from %d to %d", ErrNoEdge, from, to)
+	}
+	n.nodes[from].out = dropEdge(n.nodes[from].out, to)
+	n.nodes[to].in = dropEdge(n.nodes[to].in, from)
+	return nil
+}
+
+// Edge returns the cost of the edge from one node to another, if there is
+// one.
+func (n *Network) Edge(from, to int) (float64, bool) {
+	if !n.HasNode(from) {
+		return 0, false
+	}
+	for _, e := range n.nodes[from].out {
+		if e.To == to {
+			return e.Cost, true
+		}
+	}
+	return 0, false
+}
+
+// EdgesFrom returns the edges leaving id, in the order they were added.
+func (n *Network) EdgesFrom(id int) []NetworkEdge {
+	if !n.HasNode(id) {
+		return nil
+	}
+	return append([]NetworkEdge(nil), n.nodes[id].out...)
+}
+
+// EdgesTo returns the edges entering id, with To set to their source.
+func (n *Network) EdgesTo(id int) []NetworkEdge {
+	if !n.HasNode(id) {
+		return nil
+	}
+	return append([]NetworkEdge(nil), n.nodes[id].in...)
+}
+
+func dropEdge(edges []NetworkEdge, to int) []NetworkEdge {
+	for i, e := range edges {
+		if e.To == to {
+			return append(edges[:i], edges[i+1:]...)
+		}
+	}
+	return edges
+}
+
+// ShortestPath runs Dijkstra's algorithm from one node to another. It
+// honours WithQueue and WithContext; the grid options do not apply. It
+// returns ErrNoPath if to cannot be reached.
+func (n *Network) ShortestPath(from, to int, opts ...Option) (NetworkPath, error) {
+	if !n.HasNode(from) {
+		return NetworkPath{}, fmt.Errorf("%w: %d", ErrNoNode, from)
+	}
+	if !n.HasNode(to) {
+		return NetworkPath{}, fmt.Errorf("%w: %d", ErrNoNode, to)
+	}
+	o := buildOptions(opts)
+	size := len(n.nodes)
+	dist := make([]float64, size)
+	prev := make([]int, size)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	done := make([]bool, size)
+	dist[from] = 0
+	pq := o.newQueue(size)
+	pq.Push(from, 0)
+	for expanded := 1; pq.Len() > 0; expanded++ {
+		cur, d := pq.PopMin()
+		done[cur] = true
+		if cur == to {
+			break
+		}
+		if o.Context != nil && expanded%cancelCheckInterval == 0 {
+			if err := o.Context.Err(); err != nil {
+				return NetworkPath{}, err
+			}
+		}
+		for _, e := range n.nodes[cur].out {
+			nd := d + e.Cost
+			if done[e.To] || nd >= dist[e.To] {
+				continue
+			}
+			fresh := math.IsInf(dist[e.To], 1)
+			dist[e.To], prev[e.To] = nd, cur
+			if fresh {
+				pq.Push(e.To, nd)
+			} else {
+				pq.DecreaseKey(e.To, nd)
+			}
+		}
+	}
+	if math.IsInf(dist[to], 1) {
+		return NetworkPath{}, ErrNoPath
+	}
+	var rev []int
+	for id := to; id != -1; id = prev[id] {
+		rev = append(rev, id)
+	}
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return NetworkPath{Nodes: rev, Cost: dist[to]}, nil
+}
@@ -0,0 +1,117 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestNetworkEdits(t *testing.T) {
+	n := dijkstrapf.NewNetwork()
+	a, b, c, d := n.AddNode(), n.AddNode(), n.AddNode(), n.AddNode()
+	for _, e := range []struct {
+		from, to int
+		cost     float64
+	}{{a, b, 1}, {b, d, 1}, {a, c, 2}, {c, d, 2}} {
+		if err := n.AddEdge(e.from, e.to, e.cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(want []int, cost float64) {
+		t.Helper()
+		p, err := n.ShortestPath(a, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(p.Nodes, want) || p.Cost != cost {
+			t.Fatalf("got %v cost %g, want %v cost %g", p.Nodes, p.Cost, want, cost)
+		}
+	}
+	check([]int{a, b, d}, 2)
+
+	if err := n.RemoveNode(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := n.EdgesFrom(a); len(got) != 1 || got[0].To != c {
+		t.Fatalf("edges from a after removing b: %v", got)
+	}
+	if got := n.EdgesTo(d); len(got) != 1 || got[0].To != c {
+		t.Fatalf("edges to d after removing b: %v", got)
+	}
+	check([]int{a, c, d}, 4)
+
+	if err := n.RemoveEdge(c, d); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.ShortestPath(a, d); !errors.Is(err, dijkstrapf.ErrNoPath) {
+		t.Fatalf("err = %v, want ErrNoPath", err)
+	}
+	if err := n.RemoveEdge(c, d); !errors.Is(err, dijkstrapf.ErrNoEdge) {
+		t.Fatalf("err = %v, want ErrNoEdge", err)
+	}
+
+	if err := n.RestoreNode(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := n.EdgesFrom(b); len(got) != 0 {
+		t.Fatalf("restored node has edges %v", got)
+	}
+	n.AddEdge(a, b, 1)
+	n.AddEdge(b, d, 5)
+	check([]int{a, b, d}, 6)
+	if err := n.AddEdge(a, 99, 1); !errors.Is(err, dijkstrapf.ErrNoNode) {
+		t.Fatalf("err = %v, want ErrNoNode", err)
+	}
+}
+
+func TestKeyedNetwork(t *testing.T) {
+	n := dijkstrapf.NewKeyedNetwork[string]()
+	for _, city := range []string{"Oslo", "Göteborg", "Stockholm", "Malmö"} {
+		if err := n.AddNode(city, map[string]any{"country": "?"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := n.AddNode("Oslo", nil); !errors.Is(err, dijkstrapf.ErrNodeExists) {
+		t.Fatalf("err = %v, want ErrNodeExists", err)
+	}
+	road := func(a, b string, km float64) {
+		n.AddEdge(a, b, km)
+		n.AddEdge(b, a, km)
+	}
+	road("Oslo", "Göteborg", 290)
+	road("Oslo", "Stockholm", 520)
+	road("Göteborg", "Stockholm", 470)
+	road("Göteborg", "Malmö", 270)
+	road("Stockholm", "Malmö", 610)
+
+	p, err := n.ShortestPath("Oslo", "Malmö")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Oslo", "Göteborg", "Malmö"}; !reflect.DeepEqual(p.Nodes, want) || p.Cost != 560 {
+		t.Fatalf("got %v cost %g, want %v cost 560", p.Nodes, p.Cost, want)
+	}
+
+	n.RemoveNode("Göteborg")
+	p, err = n.ShortestPath("Oslo", "Malmö")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Oslo", "Stockholm", "Malmö"}; !reflect.DeepEqual(p.Nodes, want) {
+		t.Fatalf("got %v, want %v", p.Nodes, want)
+	}
+	if _, ok := n.Metadata("Göteborg"); ok {
+		t.Fatal("removed node still has metadata")
+	}
+	if err := n.AddNode("Göteborg", map[string]any{"country": "SE"}); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := n.Metadata("Göteborg"); m["country"] != "SE" {
+		t.Fatalf("metadata = %v", m)
+	}
+	if got := n.Keys(); !reflect.DeepEqual(got, []string{"Oslo", "Göteborg", "Stockholm", "Malmö"}) {
+		t.Fatalf("Keys() = %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package dijkstrapf
+
+// ChangeKind says what about a cell changed.
+type ChangeKind int
+
+const (
+	// ChangeWall means the cell became a wall or stopped being one.
+	ChangeWall ChangeKind = iota
+	// ChangeWeight means the cost of entering the cell changed.
+	ChangeWeight
+	// ChangeTerrain means the cell was given a named terrain.
+	ChangeTerrain
+	// ChangeMarker means the start or goal moved onto or off the cell.
+	ChangeMarker
+	// ChangeLayout means a setting of the whole grid changed, such as
+	// diagonal moves, wrapping or the extra edges. Its At is (-1,-1).
+	ChangeLayout
+	// ChangeDoor means a door was placed on or taken off the cell, or was
+	// opened, closed or locked.
+	ChangeDoor
+	// ChangeRisk means the blockage probability of the cell changed.
+	ChangeRisk
+	// ChangeKey means a key was placed on the cell.
+	ChangeKey
+)
+
+// Change describes one mutation of a graph. Observers read the new state
+// from the graph itself.
+type Change struct {
+	Kind ChangeKind
+	At   Point
+}
+
+// OnChange registers f to be called after every successful mutation of
+// the graph, once per affected cell. Moving the start or goal reports both
+// the cell it left and the cell it moved to. f must not mutate the graph.
+// The returned function unregisters f.
+func (g *Graph) OnChange(f func(Change)) (cancel func()) {
+	g.observers = append(g.observers, f)
+	i := len(g.observers) - 1
+	return func() { g.observers[i] = nil }
+}
+
+// changed notifies the observers of a change to p.
+func (g *Graph) changed(k ChangeKind, p Point) {
+	g.fingerprinted = false
+	for _, f := range g.observers {
+		if f != nil {
+			f(Change{k, p})
+		}
+	}
+}
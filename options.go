@@ -0,0 +1,95 @@
+package dijkstrapf
+
+import (
+	"context"
+	"io"
+	"math"
+)
+
+// CostFunc returns the cost of stepping from one cell to a neighbouring one.
+// Returning +Inf blocks the step.
+type CostFunc func(from, to Point) float64
+
+// Options configures a single solve. Use the With* functions to set them.
+type Options struct {
+	// Cost overrides the stored cell weights for this solve only.
+	Cost CostFunc
+	// Queue creates the frontier; nil selects a binary heap.
+	Queue QueueFactory
+	// Heuristic guides the informed solvers; nil selects a grid distance.
+	Heuristic HeuristicFunc
+	// Trace receives a human-readable log of every step.
+	Trace io.Writer
+	// Events receives every step as a line of JSON.
+	Events io.Writer
+	// OnSettle is called with every settled node.
+	OnSettle func(p Point, dist float64)
+	// FullMap keeps Dijkstra's algorithm running past the goal.
+	FullMap bool
+	// Reverse makes Dijkstra's algorithm search from the goal.
+	Reverse bool
+	// Clearance weights the penalty for passing close to walls.
+	Clearance float64
+	// RiskPenalty weights the blockage probability of the cells entered.
+	RiskPenalty float64
+	// Epsilon inflates the A* heuristic; values below 1 mean 1.
+	Epsilon float64
+	// TieBreak orders frontier cells of equal priority.
+	TieBreak TieBreak
+	// MemoryLimit caps the estimated memory of a solve in bytes.
+	MemoryLimit int64
+	// MemoryFallback runs IDA* when MemoryLimit would be exceeded.
+	MemoryFallback bool
+	// Context cancels the solve when done.
+	Context context.Context
+	// Partial returns a path towards the goal when it is not reached.
+	Partial bool
+	// Tracer records the phases of the solve as spans.
+	Tracer Tracer
+
+	// expansionBudget caps the cells a memory fallback may expand.
+	expansionBudget int
+
+	// penalty caches the per-cell clearance penalty once computed.
+	penalty []float64
+}
+
+// Option changes one setting of a solve.
+type Option func(*Options)
+
+// WithCost makes the solver price every step with f instead of the stored
+// cell weights, leaving the grid itself untouched. This is the place for
+// per-query influences such as penalising cells near an enemy this frame.
+func WithCost(f CostFunc) Option {
+	return func(o *Options) { o.Cost = f }
+}
+
+func buildOptions(opts []Option) *Options {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// stepCost returns the cost of the edge e leaving from, honouring o.Cost,
+// o.Clearance and o.RiskPenalty.
+func (g *Graph) stepCost(o *Options, from int, e edge) (float64, error) {
+	c := e.cost
+	if o.Cost != nil {
+		c = o.Cost(g.point(from), g.point(e.to))
+		if c < 0 || math.IsNaN(c) {
+			return 0, ErrNegativeCost
+		}
+	}
+	if o.Clearance > 0 {
+		if o.penalty == nil {
+			o.penalty = g.clearancePenalty(o.Clearance)
+		}
+		c += o.penalty[e.to]
+	}
+	if o.RiskPenalty > 0 {
+		c += o.RiskPenalty * g.risk[e.to]
+	}
+	return c, nil
+}
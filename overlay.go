@@ -0,0 +1,108 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Overlay symbols drawn by Render and WriteOverlay.
+const (
+	SymbolPath     = 'o'
+	SymbolPathA    = 'a'
+	SymbolPathB    = 'b'
+	SymbolPathBoth = '*'
+)
+
+// PathDiff lists the cells that lie on only one of two paths, in the order
+// of the path they lie on.
+type PathDiff struct {
+	OnlyA, OnlyB []Point
+}
+
+// Same reports whether both paths visit the same cells.
+func (d PathDiff) Same() bool { return len(d.OnlyA) == 0 && len(d.OnlyB) == 0 }
+
+// DiffPaths compares the cells of a and b.
+func DiffPaths(a, b Path) PathDiff {
+	inA, inB := pointSet(a.Points), pointSet(b.Points)
+	var d PathDiff
+	for _, p := range a.Points {
+		if !inB[p] {
+			d.OnlyA = append(d.OnlyA, p)
+		}
+	}
+	for _, p := range b.Points {
+		if !inA[p] {
+			d.OnlyB = append(d.OnlyB, p)
+		}
+	}
+	return d
+}
+
+func pointSet(points []Point) map[Point]bool {
+	set := make(map[Point]bool, len(points))
+	for _, p := range points {
+		set[p] = true
+	}
+	return set
+}
+
+// WriteOverlay draws a and b on one grid, such as the paths of two solvers
+// or of one solver before and after editing the grid. Cells on only a are
+// drawn as 'a', cells on only b as 'b' and cells on both as '*', in the
+// theme's path colors if ro.Color is set. Below the grid it lists the
+// cells on only one of the paths.
+func (g *Graph) WriteOverlay(w io.Writer, a, b Path, ro RenderOptions) error {
+	marks := make(map[Point]byte, len(a.Points)+len(b.Points))
+	for _, p := range a.Points {
+		marks[p] = SymbolPathA
+	}
+	for _, p := range b.Points {
+		if marks[p] == SymbolPathA {
+			marks[p] = SymbolPathBoth
+		} else {
+			marks[p] = SymbolPathB
+		}
+	}
+	if err := g.render(w, ro, marks); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	d := DiffPaths(a, b)
+	fmt.Fprintf(bw, "\ncost: a %g, b %g\n", a.Cost, b.Cost)
+	if d.Same() {
+		fmt.Fprintln(bw, "both paths visit the same cells")
+		return bw.Flush()
+	}
+	writePoints(bw, "only on a", d.OnlyA)
+	writePoints(bw, "only on b", d.OnlyB)
+	return bw.Flush()
+}
+
+func writePoints(w *bufio.Writer, label string, points []Point) {
+	fmt.Fprintf(w, "%s (%d):", label, len(points))
+	for _, p := range points {
+		fmt.Fprintf(w, " %v", p)
+	}
+	w.WriteByte('\n')
+}
+
+// writeMarkLegend explains the overlay symbols in marked.
+func writeMarkLegend(w *bufio.Writer, marked map[byte]bool, theme *Theme, color bool) {
+	for _, m := range []struct {
+		c    byte
+		text string
+	}{
+		{SymbolPath, "on the path"},
+		{SymbolPathA, "only on path a"},
+		{SymbolPathB, "only on path b"},
+		{SymbolPathBoth, "on both paths"},
+	} {
+		if marked[m.c] {
+			writeColored(w, m.c, theme.markColor(m.c), color)
+			fmt.Fprintf(w, " %s\n", m.text)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package dijkstrapf
+
+import (
+	"container/heap"
+	"math"
+)
+
+// ParetoPath is one path of a two-objective search. Costs[0] is priced like
+// an ordinary solve, Costs[1] by the second cost function.
+type ParetoPath struct {
+	Points []Point
+	Costs  [2]float64
+}
+
+// FindPareto returns every Pareto-optimal path from the start to the goal
+// when each step has two costs: the usual one, honouring opts, and the one
+// returned by second, such as the risk of entering a cell. No returned path
+// is beaten on both costs by another path. The paths are ordered by
+// increasing first cost and therefore decreasing second cost.
+//
+// The number of Pareto-optimal paths can grow quickly with the size of the
+// grid when the two costs disagree a lot.
+func (g *Graph) FindPareto(second CostFunc, opts ...Option) ([]ParetoPath, error) {
+	o := buildOptions(opts)
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return nil, err
+	}
+	adj := g.adjacency()
+
+	// settled holds, per node, the indices of its non-dominated labels in
+	// the order they were settled: increasing first cost, decreasing second.
+	var labels []label
+	settled := make([][]int, g.nodeCount())
+	pq := &labelQueue{labels: &labels}
+	labels = append(labels, label{node: src, parent: -1})
+	heap.Push(pq, 0)
+	for pq.Len() > 0 {
+		li := heap.Pop(pq).(int)
+		l := labels[li]
+		if dominated(labels, settled[l.node], l.costs) || dominated(labels, settled[dst], l.costs) {
+			continue
+		}
+		settled[l.node] = append(settled[l.node], li)
+		if l.node == dst {
+			continue
+		}
+		for _, e := range adj[l.node] {
+			c0, err := g.stepCost(o, l.node, e)
+			if err != nil {
+				return nil, err
+			}
+			c1 := second(g.point(l.node), g.point(e.to))
+			if c1 < 0 || math.IsNaN(c1) {
+				return nil, ErrNegativeCost
+			}
+			costs := [2]float64{l.costs[0] + c0, l.costs[1] + c1}
+			if math.IsInf(costs[0], 1) || math.IsInf(costs[1], 1) || dominated(labels, settled[e.to], costs) {
+				continue
+			}
+			labels = append(labels, label{node: e.to, parent: li, costs: costs})
+			heap.Push(pq, len(labels)-1)
+		}
+	}
+
+	if len(settled[dst]) == 0 {
+		return nil, ErrNoPath
+	}
+	out := make([]ParetoPath, len(settled[dst]))
+	for i, li := range settled[dst] {
+		out[i] = ParetoPath{Points: labelPoints(g, labels, li), Costs: labels[li].costs}
+	}
+	return out, nil
+}
+
+// label is one partial path of the Pareto search.
+type label struct {
+	node, parent int
+	costs        [2]float64
+}
+
+// dominated reports whether one of the settled labels is at least as good
+// as costs on both objectives. Settled labels were popped in lexicographic
+// order, so only their second cost needs checking against the last one that
+// is not worse on the first.
+func dominated(labels []label, settled []int, costs [2]float64) bool {
+	for i := len(settled) - 1; i >= 0; i-- {
+		s := labels[settled[i]].costs
+		if s[0] <= costs[0] {
+			return s[1] <= costs[1]
+		}
+	}
+	return false
+}
+
+// labelQueue orders label indices lexicographically by their costs.
+type labelQueue struct {
+	labels *[]label
+	items  []int
+}
+
+func (q *labelQueue) Len() int { return len(q.items) }
+
+func (q *labelQueue) Less(i, j int) bool {
+	a, b := (*q.labels)[q.items[i]].costs, (*q.labels)[q.items[j]].costs
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
+
+func (q *labelQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *labelQueue) Push(x any) { q.items = append(q.items, x.(int)) }
+
+func (q *labelQueue) Pop() any {
+	x := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return x
+}
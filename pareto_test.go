@@ -0,0 +1,88 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestFindPareto(t *testing.T) {
+	hazard := dijkstrapf.Point{X: 2}
+	risky := func(_, to dijkstrapf.Point) float64 {
+		if to == hazard {
+			return 10
+		}
+		return 0
+	}
+	steps := func(_, _ dijkstrapf.Point) float64 { return 1 }
+	tests := []struct {
+		name   string
+		grid   string
+		second dijkstrapf.CostFunc
+		costs  [][2]float64
+		err    error
+	}{
+		{
+			name:   "costs agree",
+			grid:   "S...G\n.###.\n.....\n",
+			second: steps,
+			costs:  [][2]float64{{4, 4}},
+		},
+		{
+			name:   "short path is risky",
+			grid:   "S...G\n.###.\n.....\n",
+			second: risky,
+			costs:  [][2]float64{{4, 10}, {8, 0}},
+		},
+		{
+			name:   "only the risky path",
+			grid:   "S...G\n####.\n.....\n",
+			second: risky,
+			costs:  [][2]float64{{4, 10}},
+		},
+		{
+			name:   "no path",
+			grid:   "S.#.G\n..#..\n",
+			second: risky,
+			err:    dijkstrapf.ErrNoPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tt.grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths, err := g.FindPareto(tt.second)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(paths) != len(tt.costs) {
+				t.Fatalf("got %d paths, want %d", len(paths), len(tt.costs))
+			}
+			want, err := g.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if paths[0].Costs[0] != want.Cost {
+				t.Fatalf("cheapest first cost %g, FindPath gives %g", paths[0].Costs[0], want.Cost)
+			}
+			for i, p := range paths {
+				if p.Costs != tt.costs[i] {
+					t.Errorf("path %d costs %v, want %v", i, p.Costs, tt.costs[i])
+				}
+				if err := g.ValidatePath(dijkstrapf.Path{Points: p.Points, Cost: p.Costs[0]}); err != nil {
+					t.Errorf("path %d: %v", i, err)
+				}
+			}
+		})
+	}
+}
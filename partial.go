@@ -0,0 +1,66 @@
+package dijkstrapf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrPartialPath is returned together with a path that stops short of the
+// goal, as requested with WithPartial. The error also wraps the reason the
+// goal was not reached.
+var ErrPartialPath = errors.New("dijkstrapf: goal not reached")
+
+// cancelCheckInterval is the number of expansions between checks of the
+// solve's context.
+const cancelCheckInterval = 256
+
+// WithContext makes Dijkstra's algorithm, A*, JPS, fringe search and BFS
+// give up with ctx's error once ctx is done, checking every few hundred
+// expansions. Use context.WithTimeout to bound the time a solve may take.
+// Rebuilding the adjacency list after the grid changed is not interrupted.
+func WithContext(ctx context.Context) Option {
+	return func(o *Options) { o.Context = ctx }
+}
+
+// WithPartial makes a forward solve that is cancelled or finds the goal
+// unreachable return the cheapest path to the explored cell closest to the
+// goal in grid steps, with an error wrapping ErrPartialPath and the
+// original error, instead of no path at all. This lets an agent at least
+// head the right way. The solvers honouring WithContext support it.
+func WithPartial() Option {
+	return func(o *Options) { o.Partial = true }
+}
+
+// cancelled returns the context's error if the solve should stop.
+func (g *Graph) cancelled(o *Options) error {
+	if o.Context == nil || g.stats.Expanded%cancelCheckInterval != 0 {
+		return nil
+	}
+	return o.Context.Err()
+}
+
+// partial returns the path to the explored cell closest to the goal if o
+// asks for one, or cause alone otherwise.
+func (g *Graph) partial(o *Options, dist []float64, prev []int, cause error) (Path, error) {
+	if !o.Partial || g.reversed || g.closed == nil {
+		return Path{}, cause
+	}
+	// The heuristic may be zero everywhere, such as with WithCost, so
+	// closeness is measured on the grid alone.
+	best, near := -1, math.Inf(1)
+	for id, done := range g.closed {
+		if !done || math.IsInf(dist[id], 1) {
+			continue
+		}
+		if d := g.gridDistance(g.delta(g.point(id), g.goal)); d < near || d == near && dist[id] < dist[best] {
+			best, near = id, d
+		}
+	}
+	if best < 0 {
+		return Path{}, cause
+	}
+	path := Path{Points: g.reconstruct(prev, best), Cost: dist[best]}
+	return path, fmt.Errorf("%w: %w", ErrPartialPath, cause)
+}
@@ -0,0 +1,48 @@
+package dijkstrapf_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestPartialCancelled(t *testing.T) {
+	rows := make([]string, 30)
+	for y := range rows {
+		rows[y] = strings.Repeat(".", 30)
+	}
+	rows[0] = "S" + rows[0][1:]
+	rows[29] = rows[29][:29] + "G"
+	unit := func(from, to dijkstrapf.Point) float64 { return 1 }
+	tests := []struct {
+		name string
+		opts []dijkstrapf.Option
+	}{
+		{"weights", nil},
+		{"cost function", []dijkstrapf.Option{dijkstrapf.WithCost(unit)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(strings.Join(rows, "\n") + "\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			opts := append(tt.opts, dijkstrapf.WithContext(ctx), dijkstrapf.WithPartial())
+			p, err := g.FindPath(opts...)
+			if !errors.Is(err, dijkstrapf.ErrPartialPath) || !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want a cancelled partial path", err)
+			}
+			if len(p.Points) < 2 {
+				t.Fatalf("partial path %v does not leave the start", p.Points)
+			}
+			if p.Cost != float64(len(p.Points)-1) {
+				t.Fatalf("cost %g over %d points", p.Cost, len(p.Points))
+			}
+		})
+	}
+}
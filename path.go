@@ -0,0 +1,150 @@
+package dijkstrapf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Path is a sequence of cells from the start to the goal, inclusive, together
+// with the total cost of walking it.
+type Path struct {
+	Points []Point
+	Cost   float64
+}
+
+// Len returns the number of steps in the path.
+func (p Path) Len() int {
+	if len(p.Points) == 0 {
+		return 0
+	}
+	return len(p.Points) - 1
+}
+
+// reconstruct walks prev back from target and returns the points in order.
+func (g *Graph) reconstruct(prev []int, target int) []Point {
+	_, span := g.tracing.start("dijkstrapf.reconstruct")
+	defer span.End()
+	var rev []Point
+	for n := target; n != -1; n = prev[n] {
+		rev = append(rev, g.point(n))
+	}
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return rev
+}
+
+// walk follows prev forward from source until the root of the tree and
+// returns the points in walking order.
+func (g *Graph) walk(prev []int, source int) []Point {
+	var out []Point
+	for n := source; n != -1; n = prev[n] {
+		out = append(out, g.point(n))
+	}
+	return out
+}
+
+// PathTo returns the shortest path from the start to (x, y) using the
+// search tree left by the most recent solve, without searching again. After
+// a WithReverse solve it returns the path from (x, y) to the goal instead.
+// Solve with WithFullMap first to make every reachable cell available;
+// cells the search did not settle yield ErrNoPath.
+func (g *Graph) PathTo(x, y int) (Path, error) {
+	p := Point{x, y}
+	if !g.InBounds(p) {
+		return Path{}, ErrOutOfBounds
+	}
+	if g.closed == nil {
+		return Path{}, ErrNotSolved
+	}
+	id := g.id(p)
+	if !g.closed[id] {
+		return Path{}, ErrNoPath
+	}
+	if g.reversed {
+		return Path{Points: g.walk(g.prev, id), Cost: g.dist[id]}, nil
+	}
+	return Path{Points: g.reconstruct(g.prev, id), Cost: g.dist[id]}, nil
+}
+
+// compass names the unit steps, indexed by dy+1 and dx+1. Diagonal steps
+// are lower case so that the compact form stays unambiguous.
+var compass = [3][3]string{
+	{"nw", "N", "ne"},
+	{"W", "", "E"},
+	{"sw", "S", "se"},
+}
+
+// stepName names the move from a to b: a compass direction for moves to a
+// neighbouring cell, "*" for anything else, such as a portal or a move
+// across the edge of a wrapping grid.
+func stepName(a, b Point) string {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	if dx < -1 || dx > 1 || dy < -1 || dy > 1 || dx == 0 && dy == 0 {
+		return "*"
+	}
+	return compass[dy+1][dx+1]
+}
+
+// Directions returns the path as one compass letter per step, such as
+// "EENNEESS". North is up. Diagonal steps are written in lower case
+// ("ne", "se", "sw", "nw") and steps between cells that are not neighbours
+// as "*".
+func (p Path) Directions() string {
+	var b strings.Builder
+	for i := 1; i < len(p.Points); i++ {
+		b.WriteString(stepName(p.Points[i-1], p.Points[i]))
+	}
+	return b.String()
+}
+
+// RunLengthDirections returns the directions of the path with repeated
+// steps collapsed, such as "2E 2N 2E 2S".
+func (p Path) RunLengthDirections() string {
+	var runs []string
+	for i := 1; i < len(p.Points); {
+		name := stepName(p.Points[i-1], p.Points[i])
+		n := 1
+		for i+n < len(p.Points) && stepName(p.Points[i+n-1], p.Points[i+n]) == name {
+			n++
+		}
+		runs = append(runs, strconv.Itoa(n)+name)
+		i += n
+	}
+	return strings.Join(runs, " ")
+}
+
+// PathMetrics describes the shape of a path rather than its cost.
+type PathMetrics struct {
+	// Steps is the number of moves, as returned by Len.
+	Steps int
+	// Turns counts the changes of direction between consecutive moves.
+	Turns int
+	// LongestStraight is the most moves made in a row in one direction.
+	LongestStraight int
+	// Euclidean is the length of the path measured in cell widths, so a
+	// diagonal move counts √2.
+	Euclidean float64
+}
+
+// Metrics measures the geometry of the path.
+func (p Path) Metrics() PathMetrics {
+	m := PathMetrics{Steps: p.Len()}
+	run := 0
+	for i := 1; i < len(p.Points); i++ {
+		a, b := p.Points[i-1], p.Points[i]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		m.Euclidean += math.Hypot(float64(dx), float64(dy))
+		if i >= 2 {
+			z := p.Points[i-2]
+			if a.X-z.X != dx || a.Y-z.Y != dy {
+				m.Turns++
+				run = 0
+			}
+		}
+		run++
+		m.LongestStraight = max(m.LongestStraight, run)
+	}
+	return m
+}
@@ -0,0 +1,24 @@
+package dijkstrapf
+
+import "errors"
+
+// ErrNoWaypoints is returned by Patrol when it is given no waypoints.
+var ErrNoWaypoints = errors.New("dijkstrapf: no waypoints given")
+
+// Patrol returns a closed loop that visits every waypoint, starting and
+// ending at waypoints[0], together with the order in which the loop visits
+// them as indices into waypoints. The order is found from the shortest-path
+// costs between waypoints; like VisitAll it is optimal for up to 16
+// waypoints and near-optimal beyond that. The graph's own start and goal
+// are not used.
+func (g *Graph) Patrol(waypoints []Point, opts ...Option) (Path, []int, error) {
+	if len(waypoints) == 0 {
+		return Path{}, nil, ErrNoWaypoints
+	}
+	legs, err := g.legs(waypoints, opts)
+	if err != nil {
+		return Path{}, nil, err
+	}
+	order := tourOrder(len(waypoints), legs.cost, true)
+	return legs.join(order), order[:len(order)-1], nil
+}
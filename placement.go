@@ -0,0 +1,78 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// ErrNoPlacement is returned by RandomizeEndpoints when no walkable cells
+// are far enough apart.
+var ErrNoPlacement = errors.New("dijkstrapf: no reachable cells far enough apart")
+
+// placementTries bounds the number of start cells RandomizeEndpoints tries.
+const placementTries = 32
+
+// RandomizeEndpoints moves the start and goal to random walkable cells at
+// least minDistance apart, counted in moves on an empty grid, such that
+// the goal is reachable from the start. The same seed always gives the same
+// placement on the same grid. The markers are left alone if it returns
+// ErrNoPlacement.
+func (g *Graph) RandomizeEndpoints(seed int64, minDistance float64) error {
+	return g.RandomizeEndpointsFrom(rand.New(rand.NewSource(seed)), minDistance)
+}
+
+// RandomizeEndpointsFrom is RandomizeEndpoints drawing from r.
+func (g *Graph) RandomizeEndpointsFrom(r *rand.Rand, minDistance float64) error {
+	adj := g.adjacency()
+	var cells []int
+	for id := range adj {
+		p := g.point(id)
+		if !g.IsWall(p) && !math.IsInf(g.weights[p.Y][p.X], 1) {
+			cells = append(cells, id)
+		}
+	}
+	if len(cells) < 2 {
+		return ErrNoPlacement
+	}
+
+	seen := make([]int, g.nodeCount())
+	for try := 1; try <= placementTries; try++ {
+		src := cells[r.Intn(len(cells))]
+		from := g.point(src)
+		// Collect the cells reachable from src that are far enough away.
+		var far []int
+		seen[src] = try
+		queue := []int{src}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			if cur != src && g.moves(from, g.point(cur)) >= minDistance {
+				far = append(far, cur)
+			}
+			for _, e := range adj[cur] {
+				if seen[e.to] != try {
+					seen[e.to] = try
+					queue = append(queue, e.to)
+				}
+			}
+		}
+		if len(far) == 0 {
+			continue
+		}
+		dst := far[r.Intn(len(far))]
+		g.SetStart(from)
+		g.SetGoal(g.point(dst))
+		return nil
+	}
+	return ErrNoPlacement
+}
+
+// moves returns the number of moves between a and b on an empty grid.
+func (g *Graph) moves(a, b Point) float64 {
+	dx, dy := g.delta(a, b)
+	if g.diagonal {
+		return float64(max(dx, dy))
+	}
+	return float64(dx + dy)
+}
@@ -0,0 +1,132 @@
+package dijkstrapf
+
+import "math"
+
+// Default parameters of the field FindPathPotential follows.
+const (
+	DefaultRepulsion       = 2.0
+	DefaultRepulsionRadius = 3
+)
+
+// PotentialField assigns every walkable cell a potential: its straight-line
+// distance to the goal, which pulls towards it, plus a push away from the
+// walls nearby. An agent steers by stepping to its lowest neighbour.
+type PotentialField struct {
+	g *Graph
+	u []float64
+}
+
+// PotentialField builds the field of the grid for its current goal. A cell
+// whose clearance c (see WithClearance) is at most radius gets an extra
+// repulsion * (radius+1-c) / radius, so cells beside a wall get the full
+// repulsion. The repulsion fades out within radius of the goal, so that a
+// goal next to a wall still lies at the bottom of the field.
+func (g *Graph) PotentialField(repulsion float64, radius int) (*PotentialField, error) {
+	if !g.hasGoal {
+		return nil, ErrNoGoal
+	}
+	room := g.clearance()
+	u := make([]float64, g.nodeCount())
+	for id := range u {
+		p := g.point(id)
+		if g.gridMatrix[p.Y][p.X] == Wall {
+			u[id] = math.Inf(1)
+			continue
+		}
+		dx, dy := g.delta(p, g.goal)
+		d := math.Hypot(float64(dx), float64(dy))
+		u[id] = d
+		if c := room[id]; c > 0 && c <= radius {
+			push := repulsion * float64(radius+1-c) / float64(radius)
+			u[id] += push * min(1, d/float64(radius))
+		}
+	}
+	return &PotentialField{g, u}, nil
+}
+
+// Potential returns the potential of p; walls and points outside the grid
+// have +Inf.
+func (f *PotentialField) Potential(p Point) float64 {
+	if !f.g.InBounds(p) {
+		return math.Inf(1)
+	}
+	return f.u[f.g.id(p)]
+}
+
+// Steer returns the neighbour of p with the lowest potential, which is
+// where an agent at p should head. It returns false if no neighbour is
+// lower than p itself: p is the goal or a local minimum of the field.
+func (f *PotentialField) Steer(p Point) (Point, bool) {
+	if !f.g.InBounds(p) {
+		return Point{}, false
+	}
+	next, ok := f.steer(f.g.id(p))
+	return f.g.point(next), ok
+}
+
+func (f *PotentialField) steer(cur int) (int, bool) {
+	best, ok := cur, false
+	for _, e := range f.g.adjacency()[cur] {
+		if !math.IsInf(e.cost, 1) && f.u[e.to] < f.u[best] {
+			best, ok = e.to, true
+		}
+	}
+	return best, ok
+}
+
+// FindPathPotential walks down the potential field of the default
+// parameters from the start until it reaches the goal. Fields have local
+// minima, such as the inside of a U-shaped wall facing the goal; on
+// reaching one it gives up on the field and returns the shortest path
+// found by Dijkstra's algorithm instead.
+func (g *Graph) FindPathPotential(opts ...Option) (Path, error) {
+	return g.run("potential", potential, opts)
+}
+
+func potential(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	f, err := g.PotentialField(DefaultRepulsion, DefaultRepulsionRadius)
+	if err != nil {
+		return Path{}, err
+	}
+	adj := g.adjacency()
+	dist, prev := g.resetSearch()
+	dist[src] = 0
+	for cur := src; cur != dst; {
+		g.settle(o, cur, dist[cur])
+		if err := g.cancelled(o); err != nil {
+			return g.partial(o, dist, prev, err)
+		}
+		next, ok := f.steer(cur)
+		c := math.Inf(1)
+		for _, e := range adj[cur] {
+			if ok && e.to == next {
+				if c, err = g.stepCost(o, cur, e); err != nil {
+					return Path{}, err
+				}
+				break
+			}
+		}
+		// The field only falls, so it can never come back to a cell it
+		// left; a step it cannot afford is as much a dead end as a minimum.
+		if !ok || math.IsInf(c, 1) {
+			return dijkstra(g, o)
+		}
+		g.relax(o, next, dist[next], dist[cur]+c)
+		dist[next], prev[next] = dist[cur]+c, cur
+		cur = next
+	}
+	g.settle(o, dst, dist[dst])
+	g.finish(o, dst, dist[dst])
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, nil
+}
+
+func init() {
+	Register("potential", potential)
+}
@@ -0,0 +1,195 @@
+package dijkstrapf
+
+import "math"
+
+// Pursuit chases a moving goal: a hunter walks towards the goal one cell at
+// a time while the goal may move between its steps, as a fleeing target
+// does. Instead of searching afresh after every move, it repairs one
+// Dijkstra search tree rooted at the hunter. Moving the goal only grows
+// the tree until the new goal is settled, if it is not already; a step of
+// the hunter keeps the part of the tree below the cell it steps to, whose
+// distances stay exact, and throws away the rest.
+//
+// A Pursuit has its own copy of the search state and leaves the graph's
+// start, goal and last solve alone. The graph must not be edited while
+// the pursuit is in use; start a new one after editing it.
+type Pursuit struct {
+	g      *Graph
+	o      *Options
+	adj    [][]edge
+	hunter int
+	target int
+
+	dist   []float64
+	prev   []int
+	closed []bool
+	queued []bool
+	open   PriorityQueue
+
+	expanded int
+}
+
+// Pursue starts a pursuit from the graph's start towards its goal, pricing
+// the steps with opts.
+func (g *Graph) Pursue(opts ...Option) (*Pursuit, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return nil, err
+	}
+	o := buildOptions(opts)
+	if o.Reverse {
+		return nil, errForwardOnly
+	}
+	n := g.nodeCount()
+	p := &Pursuit{
+		g: g, o: o, adj: g.adjacency(), hunter: src, target: dst,
+		dist: make([]float64, n), prev: make([]int, n),
+		closed: make([]bool, n), queued: make([]bool, n),
+	}
+	for i := range p.dist {
+		p.dist[i] = math.Inf(1)
+		p.prev[i] = -1
+	}
+	p.dist[src] = 0
+	p.open = o.newQueue(n)
+	p.open.Push(src, 0)
+	p.queued[src] = true
+	return p, nil
+}
+
+// Pos returns the cell the hunter stands on.
+func (p *Pursuit) Pos() Point { return p.g.point(p.hunter) }
+
+// Goal returns the cell the hunter is chasing.
+func (p *Pursuit) Goal() Point { return p.g.point(p.target) }
+
+// Expanded returns the number of cells settled so far over the whole
+// pursuit, for comparing its cost with that of solving afresh.
+func (p *Pursuit) Expanded() int { return p.expanded }
+
+// SetGoal moves the goal to (x, y). The search catches up the next time
+// the path is asked for.
+func (p *Pursuit) SetGoal(x, y int) error {
+	q := Point{x, y}
+	if !p.g.InBounds(q) {
+		return ErrOutOfBounds
+	}
+	p.target = p.g.id(q)
+	return nil
+}
+
+// Path returns the shortest path from the hunter to the goal, growing the
+// search tree as far as needed to settle the goal.
+func (p *Pursuit) Path() (Path, error) {
+	if err := p.grow(); err != nil {
+		return Path{}, err
+	}
+	if !p.closed[p.target] {
+		return Path{}, ErrNoPath
+	}
+	return Path{Points: p.g.reconstruct(p.prev, p.target), Cost: p.dist[p.target]}, nil
+}
+
+// Step moves the hunter one cell along the shortest path to the goal and
+// returns the cell it moved to. A hunter already on the goal stays put.
+func (p *Pursuit) Step() (Point, error) {
+	path, err := p.Path()
+	if err != nil {
+		return p.Pos(), err
+	}
+	if len(path.Points) < 2 {
+		return p.Pos(), nil
+	}
+	if err := p.reroot(p.g.id(path.Points[1])); err != nil {
+		return p.Pos(), err
+	}
+	return p.Pos(), nil
+}
+
+// grow runs Dijkstra's algorithm on from the current frontier until the
+// goal is settled or the frontier runs out.
+func (p *Pursuit) grow() error {
+	g, o := p.g, p.o
+	for !p.closed[p.target] && p.open.Len() > 0 {
+		cur, _ := p.open.PopMin()
+		p.queued[cur] = false
+		p.closed[cur] = true
+		p.expanded++
+		for _, e := range p.adj[cur] {
+			if p.closed[e.to] {
+				continue
+			}
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return err
+			}
+			p.improve(cur, e.to, p.dist[cur]+c)
+		}
+	}
+	return nil
+}
+
+// improve lowers the tentative distance of node to d through from, if d
+// is lower.
+func (p *Pursuit) improve(from, node int, d float64) {
+	if d >= p.dist[node] {
+		return
+	}
+	p.dist[node], p.prev[node] = d, from
+	if p.queued[node] {
+		p.open.DecreaseKey(node, d)
+	} else {
+		p.open.Push(node, d)
+		p.queued[node] = true
+	}
+}
+
+// reroot moves the hunter to root, a settled child of the old root. The
+// settled cells below root keep their place in the tree, with distances
+// now measured from root; every other cell is forgotten, and the frontier
+// is rebuilt from the edges leaving the kept part.
+func (p *Pursuit) reroot(root int) error {
+	children := make([][]int, len(p.prev))
+	for v, done := range p.closed {
+		if done && p.prev[v] >= 0 {
+			children[p.prev[v]] = append(children[p.prev[v]], v)
+		}
+	}
+	keep := make([]bool, len(p.prev))
+	for stack := []int{root}; len(stack) > 0; {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		keep[v] = true
+		stack = append(stack, children[v]...)
+	}
+
+	base := p.dist[root]
+	for v := range p.dist {
+		if keep[v] {
+			p.dist[v] -= base
+		} else {
+			p.dist[v], p.prev[v] = math.Inf(1), -1
+		}
+	}
+	p.prev[root] = -1
+	p.hunter = root
+	p.closed = keep
+	clear(p.queued)
+	p.open = p.o.newQueue(len(p.dist))
+	for v, kept := range keep {
+		if !kept {
+			continue
+		}
+		for _, e := range p.adj[v] {
+			if keep[e.to] {
+				continue
+			}
+			c, err := p.g.stepCost(p.o, v, e)
+			if err != nil {
+				return err
+			}
+			p.improve(v, e.to, p.dist[v]+c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package dijkstrapf
+
+import "math"
+
+// PriorityQueue is the frontier used by the solvers. Nodes are the solver's
+// dense node indexes in [0, n) where n is the capacity the queue was created
+// with; a node is queued at most once at a time.
+type PriorityQueue interface {
+	// Push inserts node with the given priority.
+	Push(node int, priority float64)
+	// PopMin removes and returns the node with the smallest priority. It
+	// panics if the queue is empty.
+	PopMin() (node int, priority float64)
+	// DecreaseKey lowers the priority of a node that is still queued.
+	DecreaseKey(node int, priority float64)
+	// Len returns the number of queued nodes.
+	Len() int
+}
+
+// QueueFactory creates an empty queue for a graph of n nodes.
+type QueueFactory func(n int) PriorityQueue
+
+// WithQueue makes the solver use queues created by f instead of the default
+// binary heap.
+func WithQueue(f QueueFactory) Option {
+	return func(o *Options) { o.Queue = f }
+}
+
+func (o *Options) newQueue(n int) PriorityQueue {
+	if o.Queue == nil {
+		return NewBinaryHeap(n)
+	}
+	return o.Queue(n)
+}
+
+// BinaryHeap is an indexed binary min-heap supporting DecreaseKey in
+// O(log n).
+type BinaryHeap struct {
+	nodes []int
+	prio  []float64
+	pos   []int // pos[node] is the node's index in nodes, or -1
+
+	// tie, if set, orders nodes of equal priority; see tieHeap.
+	tie []float64
+}
+
+// NewBinaryHeap returns an empty heap for nodes in [0, n).
+func NewBinaryHeap(n int) *BinaryHeap {
+	h := &BinaryHeap{pos: make([]int, n)}
+	for i := range h.pos {
+		h.pos[i] = -1
+	}
+	return h
+}
+
+func (h *BinaryHeap) Len() int { return len(h.nodes) }
+
+func (h *BinaryHeap) Push(node int, priority float64) {
+	for node >= len(h.pos) {
+		h.pos = append(h.pos, -1)
+	}
+	h.nodes = append(h.nodes, node)
+	h.prio = append(h.prio, priority)
+	h.pos[node] = len(h.nodes) - 1
+	h.up(len(h.nodes) - 1)
+}
+
+func (h *BinaryHeap) PopMin() (int, float64) {
+	node, p := h.nodes[0], h.prio[0]
+	last := len(h.nodes) - 1
+	h.swap(0, last)
+	h.nodes, h.prio = h.nodes[:last], h.prio[:last]
+	h.pos[node] = -1
+	if last > 0 {
+		h.down(0)
+	}
+	return node, p
+}
+
+func (h *BinaryHeap) DecreaseKey(node int, priority float64) {
+	i := h.pos[node]
+	if i < 0 || priority >= h.prio[i] {
+		return
+	}
+	h.prio[i] = priority
+	h.up(i)
+}
+
+// remove takes node out of the heap if it is queued.
+func (h *BinaryHeap) remove(node int) {
+	if node >= len(h.pos) || h.pos[node] < 0 {
+		return
+	}
+	i, last := h.pos[node], len(h.nodes)-1
+	h.swap(i, last)
+	h.nodes, h.prio = h.nodes[:last], h.prio[:last]
+	h.pos[node] = -1
+	if i < last {
+		h.down(i)
+		h.up(i)
+	}
+}
+
+func (h *BinaryHeap) swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.prio[i], h.prio[j] = h.prio[j], h.prio[i]
+	h.pos[h.nodes[i]] = i
+	h.pos[h.nodes[j]] = j
+}
+
+// less reports whether the entry at index i comes before the one at j.
+func (h *BinaryHeap) less(i, j int) bool {
+	if h.prio[i] != h.prio[j] || h.tie == nil {
+		return h.prio[i] < h.prio[j]
+	}
+	return h.tie[h.nodes[i]] < h.tie[h.nodes[j]]
+}
+
+func (h *BinaryHeap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *BinaryHeap) down(i int) {
+	n := len(h.nodes)
+	for {
+		min := i
+		if l := 2*i + 1; l < n && h.less(l, min) {
+			min = l
+		}
+		if r := 2*i + 2; r < n && h.less(r, min) {
+			min = r
+		}
+		if min == i {
+			return
+		}
+		h.swap(i, min)
+		i = min
+	}
+}
+
+// BucketQueue is a bucket (Dial) queue. Priorities are grouped into buckets
+// of a fixed width; PopMin takes the smallest entry of the lowest non-empty
+// bucket, so results are exact for any width, and fastest when the width is
+// close to the smallest edge cost.
+type BucketQueue struct {
+	width   float64
+	buckets map[int][]int
+	prio    map[int]float64
+	lowest  int
+	size    int
+}
+
+// NewBucketQueue returns an empty bucket queue with the given bucket width.
+func NewBucketQueue(width float64) *BucketQueue {
+	if !(width > 0) {
+		width = 1
+	}
+	return &BucketQueue{
+		width:   width,
+		buckets: make(map[int][]int),
+		prio:    make(map[int]float64),
+		lowest:  math.MaxInt,
+	}
+}
+
+func (q *BucketQueue) Len() int { return q.size }
+
+func (q *BucketQueue) bucket(priority float64) int {
+	return int(math.Floor(priority / q.width))
+}
+
+func (q *BucketQueue) Push(node int, priority float64) {
+	b := q.bucket(priority)
+	q.buckets[b] = append(q.buckets[b], node)
+	q.prio[node] = priority
+	if b < q.lowest {
+		q.lowest = b
+	}
+	q.size++
+}
+
+func (q *BucketQueue) PopMin() (int, float64) {
+	if q.size == 0 {
+		panic("dijkstrapf: PopMin on an empty BucketQueue")
+	}
+	for len(q.buckets[q.lowest]) == 0 {
+		delete(q.buckets, q.lowest)
+		q.lowest = q.nextBucket()
+	}
+	bucket := q.buckets[q.lowest]
+	best := 0
+	for i, n := range bucket {
+		if q.prio[n] < q.prio[bucket[best]] {
+			best = i
+		}
+	}
+	node := bucket[best]
+	bucket[best] = bucket[len(bucket)-1]
+	q.buckets[q.lowest] = bucket[:len(bucket)-1]
+	p := q.prio[node]
+	delete(q.prio, node)
+	q.size--
+	return node, p
+}
+
+func (q *BucketQueue) DecreaseKey(node int, priority float64) {
+	old, ok := q.prio[node]
+	if !ok || priority >= old {
+		return
+	}
+	ob := q.bucket(old)
+	bucket := q.buckets[ob]
+	for i, n := range bucket {
+		if n == node {
+			bucket[i] = bucket[len(bucket)-1]
+			q.buckets[ob] = bucket[:len(bucket)-1]
+			break
+		}
+	}
+	q.size--
+	q.Push(node, priority)
+}
+
+// nextBucket finds the lowest non-empty bucket after the current one was
+// drained.
+func (q *BucketQueue) nextBucket() int {
+	next := math.MaxInt
+	for b, nodes := range q.buckets {
+		if len(nodes) > 0 && b < next {
+			next = b
+		}
+	}
+	return next
+}
@@ -0,0 +1,66 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownAlgorithm is returned by Solve for names nobody registered.
+var ErrUnknownAlgorithm = errors.New("dijkstrapf: unknown algorithm")
+
+// Solver finds a path from the start to the goal cell of g.
+type Solver func(g *Graph, o *Options) (Path, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Solver)
+)
+
+// Register makes a solver available under name. It panics if the name is
+// already taken, so it is meant to be called from init functions.
+func Register(name string, s Solver) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if s == nil {
+		panic("dijkstrapf: Register solver is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("dijkstrapf: Register called twice for " + name)
+	}
+	registry[name] = s
+}
+
+// Lookup returns the solver registered under name.
+func Lookup(name string) (Solver, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := registry[name]
+	return s, ok
+}
+
+// Algorithms returns the sorted names of all registered solvers.
+func Algorithms() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Solve runs the solver registered under algo.
+func (g *Graph) Solve(algo string, opts ...Option) (Path, error) {
+	s, ok := Lookup(algo)
+	if !ok {
+		return Path{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, algo)
+	}
+	return g.run(algo, s, opts)
+}
+
+func init() {
+	Register("dijkstra", dijkstra)
+}
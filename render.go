@@ -0,0 +1,162 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RenderOptions controls how Render draws the grid.
+type RenderOptions struct {
+	// Viewport, if set, limits the drawing to a window onto the grid.
+	Viewport *Viewport
+	// Axes prints the column numbers above the grid, one digit per line,
+	// and the row numbers to the left of it.
+	Axes bool
+	// Legend lists the symbols in the drawing below it.
+	Legend bool
+	// Color draws the symbols in ANSI colors.
+	Color bool
+	// Theme picks the colors; nil means ThemeDefault.
+	Theme *Theme
+	// Path, if set, is drawn over the empty cells it crosses as 'o'.
+	Path []Point
+}
+
+// Render draws the grid with the symbols used by WriteTerrain.
+func (g *Graph) Render(w io.Writer, ro RenderOptions) error {
+	var marks map[Point]byte
+	if len(ro.Path) > 0 {
+		marks = make(map[Point]byte, len(ro.Path))
+		for _, p := range ro.Path {
+			marks[p] = SymbolPath
+		}
+	}
+	return g.render(w, ro, marks)
+}
+
+// render draws the grid with the cells in marks drawn with their mark
+// symbol instead, except for walls, endpoints and doors.
+func (g *Graph) render(w io.Writer, ro RenderOptions, marks map[Point]byte) error {
+	theme := ro.Theme
+	if theme == nil {
+		theme = &ThemeDefault
+	}
+	v := Viewport{0, 0, g.width, g.height}
+	if ro.Viewport != nil {
+		v = g.clampViewport(*ro.Viewport)
+	}
+	bw := bufio.NewWriter(w)
+	labelWidth := 0
+	if ro.Axes {
+		labelWidth = len(strconv.Itoa(v.Y + v.Height - 1))
+		writeColumnRuler(bw, v, labelWidth)
+	}
+	used, marked := make(map[byte]bool), make(map[byte]bool)
+	for y := v.Y; y < v.Y+v.Height; y++ {
+		if ro.Axes {
+			fmt.Fprintf(bw, "%*d ", labelWidth, y)
+		}
+		for x := v.X; x < v.X+v.Width; x++ {
+			p := Point{x, y}
+			c, err := g.symbol(p)
+			if err != nil {
+				c = '?'
+			}
+			door := false
+			if d, ok := g.doorSymbol(p); ok && g.gridMatrix[y][x] == Empty {
+				c, door = d, true
+			}
+			if m, ok := marks[p]; ok && g.gridMatrix[y][x] == Empty && !door {
+				marked[m] = true
+				writeColored(bw, m, theme.markColor(m), ro.Color)
+				continue
+			}
+			used[c] = true
+			writeColored(bw, c, theme.colorOf(g, p, c), ro.Color)
+		}
+		bw.WriteByte('\n')
+	}
+	if ro.Legend {
+		bw.WriteByte('\n')
+		g.writeUsedLegend(bw, used, theme, ro.Color)
+		writeMarkLegend(bw, marked, theme, ro.Color)
+	}
+	return bw.Flush()
+}
+
+// writeColumnRuler writes the column numbers of v vertically, most
+// significant digit first, indented past the row labels.
+func writeColumnRuler(w *bufio.Writer, v Viewport, labelWidth int) {
+	digits := len(strconv.Itoa(v.X + v.Width - 1))
+	for k := digits - 1; k >= 0; k-- {
+		for i := 0; i <= labelWidth; i++ {
+			w.WriteByte(' ')
+		}
+		unit := int(math.Pow10(k))
+		for x := v.X; x < v.X+v.Width; x++ {
+			if x < unit && k > 0 {
+				w.WriteByte(' ')
+				continue
+			}
+			w.WriteByte(byte('0' + x/unit%10))
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// writeUsedLegend explains the symbols in used, in the order WriteLegend
+// lists them.
+func (g *Graph) writeUsedLegend(w *bufio.Writer, used map[byte]bool, theme *Theme, color bool) {
+	for _, t := range g.terrains {
+		if !used[t.Symbol] {
+			continue
+		}
+		writeColored(w, t.Symbol, theme.terrainColor(t), color)
+		if math.IsInf(t.Cost, 1) {
+			fmt.Fprintf(w, " %s (impassable)\n", t.Name)
+		} else {
+			fmt.Fprintf(w, " %s (cost %g)\n", t.Name, t.Cost)
+		}
+	}
+	entries := []struct {
+		c         byte
+		sgr, text string
+	}{
+		{SymbolWall, theme.Wall, "wall"},
+		{SymbolStart, theme.Start, "start"},
+		{SymbolGoal, theme.Goal, "goal"},
+		{SymbolEmpty, theme.Empty, "empty (cost 1)"},
+		{SymbolDoorOpen, theme.Weight, "open door"},
+		{SymbolDoorClosed, theme.Weight, "closed door"},
+		{SymbolDoorLocked, theme.Weight, "locked door"},
+	}
+	for _, e := range entries {
+		if used[e.c] {
+			writeColored(w, e.c, e.sgr, color)
+			fmt.Fprintf(w, " %s\n", e.text)
+		}
+	}
+	for c := byte('2'); c <= '9'; c++ {
+		if used[c] && g.terrainBySymbol(c) < 0 {
+			writeColored(w, c, theme.Weight, color)
+			fmt.Fprintf(w, " cost %c\n", c)
+		}
+	}
+	if used['?'] {
+		writeColored(w, '?', theme.Weight, color)
+		fmt.Fprintf(w, " weight with no symbol\n")
+	}
+}
+
+// RenderString returns the drawing Render would write, one line per row,
+// each ending in a newline. It is stable for a given grid and options,
+// which makes it suitable for comparing against expected output in tests.
+func (g *Graph) RenderString(ro RenderOptions) string {
+	var b strings.Builder
+	g.Render(&b, ro)
+	return b.String()
+}
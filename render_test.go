@@ -0,0 +1,104 @@
+package dijkstrapf_test
+
+import (
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+const renderMap = `S...#.....
+.##.#.###.
+....#...#.
+.5,,....#G
+`
+
+func loadRenderMap(t *testing.T) *dijkstrapf.Graph {
+	t.Helper()
+	g, err := dijkstrapf.LoadGrid(strings.NewReader(renderMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestRenderString(t *testing.T) {
+	tests := []struct {
+		name string
+		ro   dijkstrapf.RenderOptions
+		want string
+	}{
+		{"plain", dijkstrapf.RenderOptions{}, renderMap},
+		{
+			"axes",
+			dijkstrapf.RenderOptions{Axes: true},
+			`  0123456789
+0 S...#.....
+1 .##.#.###.
+2 ....#...#.
+3 .5,,....#G
+`,
+		},
+		{
+			"viewport",
+			dijkstrapf.RenderOptions{Viewport: &dijkstrapf.Viewport{X: 7, Y: 1, Width: 5, Height: 2}, Axes: true},
+			`  56789
+1 .###.
+2 ...#.
+`,
+		},
+		{
+			"legend",
+			dijkstrapf.RenderOptions{Viewport: &dijkstrapf.Viewport{X: 0, Y: 2, Width: 4, Height: 2}, Legend: true},
+			`....
+.5,,
+
+, grass (cost 2)
+. empty (cost 1)
+5 cost 5
+`,
+		},
+		{
+			"color",
+			dijkstrapf.RenderOptions{Viewport: &dijkstrapf.Viewport{X: 0, Y: 3, Width: 3, Height: 1}, Color: true},
+			".5\x1b[32m,\x1b[0m\n",
+		},
+	}
+	g := loadRenderMap(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.RenderString(tt.ro); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOverlay(t *testing.T) {
+	g := loadRenderMap(t)
+	a, err := g.Solve("dijkstra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetWall(dijkstrapf.Point{X: 8, Y: 3}, false)
+	b, err := g.Solve("dijkstra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := g.WriteOverlay(&sb, a, b, dijkstrapf.RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `S***#aaaaa
+.##*#a###a
+...*#a..#a
+.5,***bbbG
+
+cost: a 19, b 13
+only on a (9): (5,2) (5,1) (5,0) (6,0) (7,0) (8,0) (9,0) (9,1) (9,2)
+only on b (3): (6,3) (7,3) (8,3)
+`
+	if got := sb.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,147 @@
+package dijkstrapf
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// ErrTooRisky is returned by FindPathSafe when the goal can be reached,
+// but only by paths more likely to be blocked than allowed. It wraps
+// ErrNoPath.
+var ErrTooRisky = fmt.Errorf("%w: every path is too likely to be blocked", ErrNoPath)
+
+// SetBlockProbability records that p may turn out to be blocked when an
+// agent gets there, with probability prob, such as a road that may be
+// flooded. It does not change the cost of entering p; see WithRiskPenalty
+// and FindPathSafe for planning around it.
+func (g *Graph) SetBlockProbability(p Point, prob float64) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	if !(prob >= 0 && prob <= 1) {
+		return fmt.Errorf("%w: probability %g is not between 0 and 1", ErrBadWeight, prob)
+	}
+	if g.risk == nil {
+		g.risk = make([]float64, g.nodeCount())
+	}
+	g.risk[g.id(p)] = prob
+	g.changed(ChangeRisk, p)
+	return nil
+}
+
+// BlockProbability returns the probability that p is blocked.
+func (g *Graph) BlockProbability(p Point) float64 {
+	if !g.InBounds(p) || g.risk == nil {
+		return 0
+	}
+	return g.risk[g.id(p)]
+}
+
+// WithRiskPenalty makes the solver add penalty times the blockage
+// probability of every cell a step enters to the step's cost. With penalty
+// set to what finding a cell blocked costs, such as the detour back, the
+// solver minimises the expected cost of the path.
+func WithRiskPenalty(penalty float64) Option {
+	return func(o *Options) { o.RiskPenalty = penalty }
+}
+
+// PathRisk returns the probability that at least one cell of p after the
+// first is blocked, taking the cells to be blocked independently.
+func (g *Graph) PathRisk(p Path) float64 {
+	free := 1.0
+	for i := 1; i < len(p.Points); i++ {
+		free *= 1 - g.BlockProbability(p.Points[i])
+	}
+	return 1 - free
+}
+
+// FindPathSafe finds the cheapest path from the start to the goal whose
+// PathRisk is at most maxRisk. The probability that a path stays clear is
+// the product over its cells, so the search adds up -log(1-p) per cell as
+// a second cost with a budget, keeping for every cell each way of reaching
+// it that no other beats on both cost and risk, as FindPareto does.
+func (g *Graph) FindPathSafe(maxRisk float64, opts ...Option) (Path, error) {
+	return g.run("safe", func(g *Graph, o *Options) (Path, error) {
+		if o.Reverse {
+			return Path{}, errForwardOnly
+		}
+		src, dst, err := g.endpoints()
+		if err != nil {
+			return Path{}, err
+		}
+		if !(maxRisk >= 0) {
+			return Path{}, fmt.Errorf("%w: probability %g is negative", ErrBadWeight, maxRisk)
+		}
+		budget := math.Inf(1)
+		if maxRisk < 1 {
+			budget = -math.Log1p(-maxRisk)
+		}
+		adj := g.adjacency()
+
+		var labels []label
+		settled := make([][]int, g.nodeCount())
+		pq := &labelQueue{labels: &labels}
+		labels = append(labels, label{node: src, parent: -1})
+		heap.Push(pq, 0)
+		risky, goal := false, -1
+		for pq.Len() > 0 {
+			li := heap.Pop(pq).(int)
+			l := labels[li]
+			if dominated(labels, settled[l.node], l.costs) {
+				continue
+			}
+			settled[l.node] = append(settled[l.node], li)
+			g.settle(o, l.node, l.costs[0])
+			if l.node == dst {
+				goal = li
+				break
+			}
+			if err := g.cancelled(o); err != nil {
+				return Path{}, err
+			}
+			for _, e := range adj[l.node] {
+				c, err := g.stepCost(o, l.node, e)
+				if err != nil {
+					return Path{}, err
+				}
+				r := 0.0
+				if g.risk != nil {
+					r = -math.Log1p(-g.risk[e.to])
+				}
+				costs := [2]float64{l.costs[0] + c, l.costs[1] + r}
+				if costs[1] > budget {
+					risky = true
+					continue
+				}
+				if math.IsInf(costs[0], 1) || dominated(labels, settled[e.to], costs) {
+					continue
+				}
+				labels = append(labels, label{node: e.to, parent: li, costs: costs})
+				heap.Push(pq, len(labels)-1)
+			}
+		}
+
+		if goal < 0 {
+			g.finish(o, dst, math.Inf(1))
+			if risky {
+				return Path{}, ErrTooRisky
+			}
+			return Path{}, ErrNoPath
+		}
+		g.finish(o, dst, labels[goal].costs[0])
+		return Path{Points: labelPoints(g, labels, goal), Cost: labels[goal].costs[0]}, nil
+	}, opts)
+}
+
+// labelPoints returns the cells of the partial path ending in label li.
+func labelPoints(g *Graph, labels []label, li int) []Point {
+	var rev []Point
+	for j := li; j != -1; j = labels[j].parent {
+		rev = append(rev, g.point(labels[j].node))
+	}
+	for a, b := 0, len(rev)-1; a < b; a, b = a+1, b-1 {
+		rev[a], rev[b] = rev[b], rev[a]
+	}
+	return rev
+}
@@ -0,0 +1,101 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Tile names understood by LoadRoguelike besides the terrain names of a
+// new graph.
+const (
+	TileWall  = "wall"
+	TileFloor = "floor"
+	TileStart = "start"
+	TileGoal  = "goal"
+)
+
+// RoguelikeSymbols maps the symbols common in roguelike ASCII maps to the
+// tiles LoadRoguelike makes of them: '@', the player, is the start, '>',
+// the stairs down, is the goal, doors open and closed are floor, blank
+// space is solid rock and '~' is water.
+var RoguelikeSymbols = map[byte]string{
+	'#':  TileWall,
+	' ':  TileWall,
+	'.':  TileFloor,
+	'+':  TileFloor,
+	'\'': TileFloor,
+	'<':  TileFloor,
+	'@':  TileStart,
+	'>':  TileGoal,
+	'~':  "water",
+}
+
+// LoadRoguelike reads a map drawn in the conventions of roguelike games.
+// Each symbol means what RoguelikeSymbols says unless overrides maps it to
+// another tile: TileWall, TileFloor, TileStart, TileGoal or the name of one
+// of DefaultTerrains. Rows may differ in length; short rows are padded with
+// wall. There are no comment lines.
+func LoadRoguelike(r io.Reader, overrides map[byte]string) (*Graph, error) {
+	var rows []string
+	width := 0
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxRowLength)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		rows = append(rows, line)
+		width = max(width, len(line))
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: row longer than %d cells", ErrBadMap, maxRowLength)
+		}
+		return nil, err
+	}
+	for len(rows) > 0 && strings.TrimSpace(rows[len(rows)-1]) == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 || width == 0 {
+		return nil, fmt.Errorf("%w: empty map", ErrBadMap)
+	}
+
+	g := NewGraph(width, len(rows))
+	for y, row := range rows {
+		for x := 0; x < width; x++ {
+			p := Point{x, y}
+			c := byte(' ')
+			if x < len(row) {
+				c = row[x]
+			}
+			tile, ok := overrides[c]
+			if !ok {
+				tile, ok = RoguelikeSymbols[c]
+			}
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown symbol %q at %v", ErrBadMap, c, p)
+			}
+			switch tile {
+			case TileFloor:
+			case TileWall:
+				g.SetWall(p, true)
+			case TileStart:
+				if g.hasStart {
+					return nil, fmt.Errorf("%w: second start at %v", ErrBadMap, p)
+				}
+				g.SetStart(p)
+			case TileGoal:
+				if g.hasGoal {
+					return nil, fmt.Errorf("%w: second goal at %v", ErrBadMap, p)
+				}
+				g.SetGoal(p)
+			default:
+				if g.SetTerrain(p, tile) != nil {
+					return nil, fmt.Errorf("%w: unknown tile %q for symbol %q at %v", ErrBadMap, tile, c, p)
+				}
+			}
+		}
+	}
+	return g, nil
+}
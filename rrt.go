@@ -0,0 +1,208 @@
+package dijkstrapf
+
+import (
+	"math"
+	"math/rand"
+)
+
+// RRTConfig tunes FindPathRRT. Zero fields take the defaults noted.
+type RRTConfig struct {
+	// Iterations bounds the number of samples drawn (default 5000).
+	Iterations int
+	// Step is the longest edge the tree grows towards a sample, in cells
+	// (default 2).
+	Step float64
+	// GoalBias is the fraction of samples taken at the goal instead of
+	// uniformly over the grid (default 0.05).
+	GoalBias float64
+	// Star turns on RRT*: every new node picks the cheapest parent within
+	// Radius and rewires the nodes around it through itself, and the
+	// search runs all Iterations to keep shortening the path instead of
+	// stopping at the first one found.
+	Star bool
+	// Radius is the neighbourhood RRT* rewires within (default 2*Step).
+	Radius float64
+	// Seed seeds the samples.
+	Seed int64
+	// Rand, if set, supplies the samples instead of Seed.
+	Rand *rand.Rand
+}
+
+func (c RRTConfig) withDefaults() RRTConfig {
+	if c.Iterations <= 0 {
+		c.Iterations = 5000
+	}
+	if c.Step <= 0 {
+		c.Step = 2
+	}
+	if c.GoalBias <= 0 || c.GoalBias >= 1 {
+		c.GoalBias = 0.05
+	}
+	if c.Radius <= 0 {
+		c.Radius = 2 * c.Step
+	}
+	return c
+}
+
+// rrtTree is the tree of a rapidly-exploring random search. Node 0 is the
+// root.
+type rrtTree struct {
+	at       []Waypoint
+	parent   []int
+	cost     []float64
+	children [][]int
+}
+
+func (t *rrtTree) add(at Waypoint, parent int, cost float64) int {
+	i := len(t.at)
+	t.at = append(t.at, at)
+	t.parent = append(t.parent, parent)
+	t.cost = append(t.cost, cost)
+	t.children = append(t.children, nil)
+	if parent >= 0 {
+		t.children[parent] = append(t.children[parent], i)
+	}
+	return i
+}
+
+// reparent hangs node i below parent at the given cost and updates the
+// costs of everything below i.
+func (t *rrtTree) reparent(i, parent int, cost float64) {
+	old := t.children[t.parent[i]]
+	for k, c := range old {
+		if c == i {
+			t.children[t.parent[i]] = append(old[:k], old[k+1:]...)
+			break
+		}
+	}
+	t.parent[i] = parent
+	t.children[parent] = append(t.children[parent], i)
+	delta := t.cost[i] - cost
+	for stack := []int{i}; len(stack) > 0; {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		t.cost[n] -= delta
+		stack = append(stack, t.children[n]...)
+	}
+}
+
+func (t *rrtTree) nearest(p Waypoint) int {
+	best, bestD := 0, math.Inf(1)
+	for i, q := range t.at {
+		if d := distance(p, q); d < bestD {
+			best, bestD = i, d
+		}
+	}
+	return best
+}
+
+// path returns the waypoints from the root to node i.
+func (t *rrtTree) path(i int) Waypoints {
+	var out Waypoints
+	for ; i >= 0; i = t.parent[i] {
+		out = append(out, t.at[i])
+	}
+	for a, b := 0, len(out)-1; a < b; a, b = a+1, b-1 {
+		out[a], out[b] = out[b], out[a]
+	}
+	return out
+}
+
+func distance(a, b Waypoint) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// FindPathRRT plans in continuous coordinates with a rapidly-exploring
+// random tree: it grows a tree from the centre of the start towards random
+// points of the grid, in edges of at most cfg.Step cells, until an edge
+// reaches the centre of the goal. With cfg.Star set it runs RRT* instead,
+// whose paths approach the shortest one as the iterations grow.
+//
+// Only walls are obstacles, and an edge is free when LineOfSight holds
+// between the cells its ends lie in; weights, diagonal settings, wrapping
+// and added edges play no part. The result is the polyline through the
+// tree; it is not a grid path, so shorten it with straight-line cuts or
+// resample it as the application needs. ErrNoPath only means that no edge
+// reached the goal within cfg.Iterations. The same seed and grid always
+// give the same result.
+func (g *Graph) FindPathRRT(cfg RRTConfig) (Waypoints, error) {
+	if _, _, err := g.endpoints(); err != nil {
+		return nil, err
+	}
+	cfg = cfg.withDefaults()
+	r := cfg.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(cfg.Seed))
+	}
+	start, goal := centreOf(g.start), centreOf(g.goal)
+	t := &rrtTree{}
+	t.add(start, -1, 0)
+	if g.segmentFree(start, goal) {
+		return Waypoints{start, goal}, nil
+	}
+
+	// reaching holds the nodes with a free edge to the goal.
+	var reaching []int
+	for it := 0; it < cfg.Iterations; it++ {
+		sample := goal
+		if r.Float64() >= cfg.GoalBias {
+			sample = Waypoint{r.Float64() * float64(g.width), r.Float64() * float64(g.height)}
+		}
+		near := t.nearest(sample)
+		p := t.at[near]
+		if d := distance(p, sample); d > cfg.Step {
+			sample = Waypoint{p.X + (sample.X-p.X)*cfg.Step/d, p.Y + (sample.Y-p.Y)*cfg.Step/d}
+		}
+		if !g.segmentFree(p, sample) {
+			continue
+		}
+
+		parent, cost := near, t.cost[near]+distance(p, sample)
+		var around []int
+		if cfg.Star {
+			for i, q := range t.at {
+				if distance(q, sample) <= cfg.Radius && g.segmentFree(q, sample) {
+					around = append(around, i)
+					if c := t.cost[i] + distance(q, sample); c < cost {
+						parent, cost = i, c
+					}
+				}
+			}
+		}
+		n := t.add(sample, parent, cost)
+		for _, i := range around {
+			if c := cost + distance(sample, t.at[i]); c < t.cost[i] {
+				t.reparent(i, n, c)
+			}
+		}
+
+		if distance(sample, goal) <= cfg.Step && g.segmentFree(sample, goal) {
+			reaching = append(reaching, n)
+			if !cfg.Star {
+				break
+			}
+		}
+	}
+	if len(reaching) == 0 {
+		return nil, ErrNoPath
+	}
+	// Rewiring keeps changing the costs, so pick the best way in only now.
+	best := reaching[0]
+	for _, i := range reaching[1:] {
+		if t.cost[i]+distance(t.at[i], goal) < t.cost[best]+distance(t.at[best], goal) {
+			best = i
+		}
+	}
+	return append(t.path(best), goal), nil
+}
+
+// segmentFree reports whether the straight line from a to b keeps clear
+// of walls, by the line of sight between the cells they lie in.
+func (g *Graph) segmentFree(a, b Waypoint) bool {
+	return g.LineOfSight(cellOf(a), cellOf(b))
+}
+
+// cellOf returns the cell containing w.
+func cellOf(w Waypoint) Point {
+	return Point{int(math.Floor(w.X)), int(math.Floor(w.Y))}
+}
@@ -0,0 +1,84 @@
+package dijkstrapf
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SamplePath draws a random path from the start to the goal using r. Every
+// step moves closer to the goal, or no farther along a free edge added with
+// AddEdge, never returning to a cell, and a step that makes the route
+// longer than the shortest one by some excess is chosen with weight
+// exp(-excess / temperature). A temperature of 0 or below only takes steps
+// along shortest paths, breaking ties at random; higher temperatures allow
+// more varied detours. The search tree of the most recent solve is
+// replaced.
+func (g *Graph) SamplePath(r *rand.Rand, temperature float64, opts ...Option) (Path, error) {
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	o := buildOptions(opts)
+	o.Reverse = true
+	// Settling a node farther from the goal than the start means every
+	// node no farther than it is settled, which is all the walk below
+	// looks at.
+	dist, _, err := g.search(o, dst, func(n int) bool { return g.dist[n] > g.dist[src] })
+	if err != nil {
+		return Path{}, err
+	}
+	if math.IsInf(dist[src], 1) {
+		return Path{}, ErrNoPath
+	}
+	o.Reverse = false
+
+	adj := g.adjacency()
+	points := []Point{g.start}
+	cost := 0.0
+	weights := make([]float64, 0, 8)
+	steps := make([]edge, 0, 8)
+	visited := make([]bool, g.nodeCount())
+	visited[src] = true
+	for cur := src; cur != dst; {
+		weights, steps = weights[:0], steps[:0]
+		total := 0.0
+		for _, e := range adj[cur] {
+			if visited[e.to] || !g.closed[e.to] || dist[e.to] > dist[cur] {
+				continue
+			}
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return Path{}, err
+			}
+			excess := max(0, c+dist[e.to]-dist[cur])
+			var w float64
+			switch {
+			case temperature > 0:
+				w = math.Exp(-excess / temperature)
+			case excess <= 1e-9*max(1, dist[cur]):
+				w = 1
+			}
+			if w > 0 {
+				weights = append(weights, w)
+				steps = append(steps, edge{e.to, c})
+				total += w
+			}
+		}
+		if len(steps) == 0 {
+			// Only free edges among visited cells led on.
+			return Path{}, ErrNoPath
+		}
+		pick := len(steps) - 1
+		for i, x := 0, r.Float64()*total; i < len(weights); i++ {
+			if x -= weights[i]; x < 0 {
+				pick = i
+				break
+			}
+		}
+		cost += steps[pick].cost
+		cur = steps[pick].to
+		visited[cur] = true
+		points = append(points, g.point(cur))
+	}
+	return Path{Points: points, Cost: cost}, nil
+}
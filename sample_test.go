@@ -0,0 +1,57 @@
+package dijkstrapf_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestSamplePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		grid        string
+		free        [][2]dijkstrapf.Point // edges costing nothing
+		temperature float64
+	}{
+		{"open", "S...\n....\n...G\n", nil, 0},
+		{"open warm", "S...\n....\n...G\n", nil, 2},
+		{"weighted", "S5..\n.#5.\n...G\n", nil, 0},
+		{"free edge", "S#.G\n", [][2]dijkstrapf.Point{{{X: 0, Y: 0}, {X: 2, Y: 0}}}, 0},
+		{"free edge warm", "S#.G\n", [][2]dijkstrapf.Point{{{X: 0, Y: 0}, {X: 2, Y: 0}}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tt.grid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range tt.free {
+				if err := g.AddEdge(e[0], e[1], 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := g.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				p, err := g.SamplePath(r, tt.temperature)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := g.ValidatePath(p); err != nil {
+					t.Fatal(err)
+				}
+				if tt.temperature <= 0 && p.Cost != want.Cost {
+					t.Fatalf("cost %g, want %g", p.Cost, want.Cost)
+				}
+				if p.Cost < want.Cost {
+					t.Fatalf("cost %g below the shortest %g", p.Cost, want.Cost)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package dijkstrapf
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed samples/*.map
+var sampleMaps embed.FS
+
+// SampleMaps returns the sorted names of the sample maps bundled with the
+// package: spiral, rooms, random-30, open-field and classic-maze.
+func SampleMaps() []string {
+	entries, _ := sampleMaps.ReadDir("samples")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".map"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadSample returns a fresh copy of the named sample map. Every sample
+// has a start and a goal that can reach each other.
+func LoadSample(name string) (*Graph, error) {
+	f, err := sampleMaps.Open(path.Join("samples", name+".map"))
+	if err != nil {
+		return nil, fmt.Errorf("dijkstrapf: unknown sample map %q", name)
+	}
+	defer f.Close()
+	return LoadGrid(f)
+}
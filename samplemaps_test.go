@@ -0,0 +1,26 @@
+package dijkstrapf_test
+
+import (
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestLoadSample(t *testing.T) {
+	names := dijkstrapf.SampleMaps()
+	if len(names) != 5 {
+		t.Fatalf("SampleMaps() = %v, want 5 maps", names)
+	}
+	for _, name := range names {
+		g, err := dijkstrapf.LoadSample(name)
+		if err != nil {
+			t.Fatalf("LoadSample(%q): %v", name, err)
+		}
+		if _, err := g.FindPath(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := dijkstrapf.LoadSample("nope"); err == nil {
+		t.Error("LoadSample(nope) succeeded")
+	}
+}
@@ -0,0 +1,137 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrBadFactor is returned by Downscale and Upscale for factors below 1.
+var ErrBadFactor = errors.New("dijkstrapf: scale factor must be at least 1")
+
+// ScaleRule decides when Downscale turns a block of cells into a wall.
+type ScaleRule int
+
+const (
+	// ScaleAny makes a block a wall if any of its cells is one, so that the
+	// coarse grid never claims a way through that is not there.
+	ScaleAny ScaleRule = iota
+	// ScaleMost makes a block a wall if more than half of its cells are,
+	// which keeps passages narrower than a block open.
+	ScaleMost
+	// ScaleAll makes a block a wall only if all of its cells are, so that
+	// the coarse grid never misses a way through.
+	ScaleAll
+)
+
+var scaleRuleNames = []string{"any", "most", "all"}
+
+func (r ScaleRule) String() string {
+	if r >= 0 && int(r) < len(scaleRuleNames) {
+		return scaleRuleNames[r]
+	}
+	return fmt.Sprintf("ScaleRule(%d)", int(r))
+}
+
+// Downscale returns a coarser copy of g in which every block of factor x
+// factor cells becomes one cell, such as for a quick coarse solve or for
+// taming a high-resolution raster. Blocks at the right and bottom edges
+// may be smaller. Whether a block is a wall is decided by rule; an open
+// block weighs the mean weight of its open cells times factor, so that
+// costs on the coarse grid are close to those on g. The start and goal go
+// to the blocks holding them, which are kept open; if both are in one
+// block the goal takes it and the copy has no start. The movement settings
+// of g are copied; markers other than the start and goal, terrains,
+// doors, edges and the last solve are not.
+func (g *Graph) Downscale(factor int, rule ScaleRule) (*Graph, error) {
+	if factor < 1 {
+		return nil, ErrBadFactor
+	}
+	w, h := (g.width+factor-1)/factor, (g.height+factor-1)/factor
+	c := g.scaledCopy(w, h)
+	for by := 0; by < h; by++ {
+		for bx := 0; bx < w; bx++ {
+			cells, walls, weight := 0, 0, 0.0
+			for y := by * factor; y < min((by+1)*factor, g.height); y++ {
+				for x := bx * factor; x < min((bx+1)*factor, g.width); x++ {
+					cells++
+					if g.gridMatrix[y][x] == Wall || math.IsInf(g.weights[y][x], 1) {
+						walls++
+					} else {
+						weight += g.weights[y][x]
+					}
+				}
+			}
+			var wall bool
+			switch rule {
+			case ScaleMost:
+				wall = 2*walls > cells
+			case ScaleAll:
+				wall = walls == cells
+			default:
+				wall = walls > 0
+			}
+			switch {
+			case wall:
+				c.gridMatrix[by][bx] = Wall
+			case walls < cells:
+				c.weights[by][bx] = weight / float64(cells-walls) * float64(factor)
+			}
+		}
+	}
+	if g.hasStart {
+		c.openMarker(Point{g.start.X / factor, g.start.Y / factor}, float64(factor))
+		c.SetStart(Point{g.start.X / factor, g.start.Y / factor})
+	}
+	if g.hasGoal {
+		c.openMarker(Point{g.goal.X / factor, g.goal.Y / factor}, float64(factor))
+		c.SetGoal(Point{g.goal.X / factor, g.goal.Y / factor})
+	}
+	return c, nil
+}
+
+// Upscale returns a finer copy of g in which every cell becomes a block of
+// factor x factor cells with its wall and weight, for refining a coarse
+// map. The start and goal go to the middle of their blocks. Movement
+// settings are copied as by Downscale.
+func (g *Graph) Upscale(factor int) (*Graph, error) {
+	if factor < 1 {
+		return nil, ErrBadFactor
+	}
+	c := g.scaledCopy(g.width*factor, g.height*factor)
+	for y := 0; y < c.height; y++ {
+		for x := 0; x < c.width; x++ {
+			if g.gridMatrix[y/factor][x/factor] == Wall {
+				c.gridMatrix[y][x] = Wall
+			}
+			c.weights[y][x] = g.weights[y/factor][x/factor]
+		}
+	}
+	if g.hasStart {
+		c.SetStart(Point{g.start.X*factor + factor/2, g.start.Y*factor + factor/2})
+	}
+	if g.hasGoal {
+		c.SetGoal(Point{g.goal.X*factor + factor/2, g.goal.Y*factor + factor/2})
+	}
+	return c, nil
+}
+
+// scaledCopy returns an empty width x height grid with the movement
+// settings of g.
+func (g *Graph) scaledCopy(width, height int) *Graph {
+	c := NewGraph(width, height)
+	c.diagonal = g.diagonal
+	c.corners = g.corners
+	c.diagonalCost = g.diagonalCost
+	c.wrap = g.wrap
+	return c
+}
+
+// openMarker makes sure the cell at p, about to hold the start or goal, is
+// open, giving a block that was made a wall the given weight.
+func (g *Graph) openMarker(p Point, weight float64) {
+	if g.gridMatrix[p.Y][p.X] == Wall {
+		g.gridMatrix[p.Y][p.X] = Empty
+		g.weights[p.Y][p.X] = weight
+	}
+}
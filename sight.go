@@ -0,0 +1,49 @@
+package dijkstrapf
+
+// LineOfSight reports whether a straight line from a to b crosses no wall.
+// The line is drawn with Bresenham's algorithm from a to b, and both ends
+// must be in bounds and walkable. Only walls block sight; water and other
+// impassable terrain do not. The line never wraps around the grid edges.
+func (g *Graph) LineOfSight(a, b Point) bool {
+	if !g.InBounds(a) || !g.InBounds(b) {
+		return false
+	}
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	e := dx + dy
+	for p := a; ; {
+		if g.gridMatrix[p.Y][p.X] == Wall {
+			return false
+		}
+		if p == b {
+			return true
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			p.Y += sy
+		}
+	}
+}
+
+// VisibleFrom returns the walkable cells that p has a line of sight to, p
+// included, row by row. It tests every cell of the grid, so prefer
+// LineOfSight when only a few cells matter.
+func (g *Graph) VisibleFrom(p Point) []Point {
+	if !g.InBounds(p) || g.IsWall(p) {
+		return nil
+	}
+	var out []Point
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			if q := (Point{x, y}); g.LineOfSight(p, q) {
+				out = append(out, q)
+			}
+		}
+	}
+	return out
+}
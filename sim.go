@@ -0,0 +1,152 @@
+package dijkstrapf
+
+// Sim moves agents across a graph, each along its own shortest path to its
+// own goal, one cell per tick. The graph may be edited between ticks: an
+// agent whose way ahead has been walled off plans again from where it
+// stands. The graph's own start and goal are not used. Agents ignore each
+// other unless avoidance is turned on with SetAvoidance.
+type Sim struct {
+	g      *Graph
+	opts   []Option
+	agents []*Agent
+	ticks  int
+	avoid  bool
+}
+
+// Agent is one traveller of a Sim.
+type Agent struct {
+	id      int
+	pos     Point
+	goal    Point
+	route   []Point
+	replans int
+	waited  int
+	stuck   bool
+}
+
+// ID returns the index of the agent in the order it was spawned.
+func (a *Agent) ID() int { return a.id }
+
+// Pos returns the cell the agent stands on.
+func (a *Agent) Pos() Point { return a.pos }
+
+// Goal returns the cell the agent is heading for.
+func (a *Agent) Goal() Point { return a.goal }
+
+// Route returns the cells the agent still plans to walk, not counting the
+// one it stands on.
+func (a *Agent) Route() []Point { return a.route }
+
+// Arrived reports whether the agent has reached its goal.
+func (a *Agent) Arrived() bool { return a.pos == a.goal }
+
+// Stuck reports whether the agent's last attempt to plan found no way to
+// its goal. A stuck agent waits where it is and tries again every tick.
+func (a *Agent) Stuck() bool { return a.stuck }
+
+// Replans returns how often the agent had to plan again on its way.
+func (a *Agent) Replans() int { return a.replans }
+
+// Waited returns how many ticks the agent spent waiting for others to
+// make way.
+func (a *Agent) Waited() int { return a.waited }
+
+// NewSim returns a simulation without agents on g. Every plan is made with
+// Dijkstra's algorithm and opts.
+func NewSim(g *Graph, opts ...Option) *Sim {
+	return &Sim{g: g, opts: opts}
+}
+
+// Spawn places a new agent at start heading for goal and plans its path.
+// It fails, spawning nothing, if either cell is out of bounds or a wall or
+// if the goal cannot be reached.
+func (s *Sim) Spawn(start, goal Point) (*Agent, error) {
+	a := &Agent{id: len(s.agents), pos: start, goal: goal}
+	if err := s.plan(a, nil); err != nil {
+		return nil, err
+	}
+	s.agents = append(s.agents, a)
+	return a, nil
+}
+
+// plan replaces a's route by the shortest path from where it stands that
+// enters none of the cells in avoid.
+func (s *Sim) plan(a *Agent, avoid map[Point]bool) error {
+	opts := s.opts
+	if len(avoid) > 0 {
+		opts = s.detour(avoid)
+	}
+	r := s.g.SolveMany([]Query{{a.pos, a.goal}}, opts...)[0]
+	a.stuck = r.Err != nil
+	if r.Err != nil {
+		a.route = nil
+		return r.Err
+	}
+	a.route = r.Path.Points[1:]
+	return nil
+}
+
+// Agents returns the agents in the order they were spawned.
+func (s *Sim) Agents() []*Agent { return s.agents }
+
+// Positions returns the cell every agent stands on, in the order they were
+// spawned, for drawing them.
+func (s *Sim) Positions() []Point {
+	out := make([]Point, len(s.agents))
+	for i, a := range s.agents {
+		out[i] = a.pos
+	}
+	return out
+}
+
+// Ticks returns the number of times Step has been called.
+func (s *Sim) Ticks() int { return s.ticks }
+
+// Step advances the simulation by one tick: every agent that has not
+// arrived moves one cell along its route, in the order they were spawned.
+// An agent plans again first if a wall now stands anywhere on the rest of
+// its route, or if it is stuck; when no way is left it stays put. With
+// avoidance on, an agent may also wait or step aside for another one.
+func (s *Sim) Step() {
+	s.ticks++
+	var occupied map[Point]*Agent
+	if s.avoid {
+		occupied = s.occupancy()
+	}
+	for _, a := range s.agents {
+		if a.Arrived() {
+			continue
+		}
+		if a.stuck || s.blocked(a.route) {
+			if s.plan(a, nil) != nil {
+				continue
+			}
+			a.replans++
+		}
+		if occupied != nil {
+			s.advance(a, occupied)
+			continue
+		}
+		a.pos, a.route = a.route[0], a.route[1:]
+	}
+}
+
+// blocked reports whether any cell of route has become a wall.
+func (s *Sim) blocked(route []Point) bool {
+	for _, p := range route {
+		if s.g.IsWall(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Done reports whether every agent has arrived.
+func (s *Sim) Done() bool {
+	for _, a := range s.agents {
+		if !a.Arrived() {
+			return false
+		}
+	}
+	return true
+}
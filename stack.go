@@ -0,0 +1,271 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// Point3 is a cell coordinate in a Stack. Z selects the layer.
+type Point3 struct {
+	X, Y, Z int
+}
+
+func (p Point3) String() string {
+	return fmt.Sprintf("(%d,%d,%d)", p.X, p.Y, p.Z)
+}
+
+// Path3 is a path through a Stack.
+type Path3 struct {
+	Points []Point3
+	Cost   float64
+}
+
+// Len returns the number of steps in the path.
+func (p Path3) Len() int {
+	if len(p.Points) == 0 {
+		return 0
+	}
+	return len(p.Points) - 1
+}
+
+// Link connects two cells of a stack, usually on different layers.
+type Link struct {
+	From, To Point3
+	// Cost is the full cost of taking the link; the weight of the cell
+	// being entered is not added.
+	Cost float64
+	// OneWay links can only be taken from From to To.
+	OneWay bool
+}
+
+// Stack is a pile of equally sized grids, one per floor or voxel layer.
+// Each layer is an ordinary Graph edited through Layer; movement between
+// layers happens only through links and, if enabled, vertical moves. The
+// start and goal live on the stack, so markers set on individual layers are
+// ignored. Diagonal moves, unless set with SetDiagonal, corner rules and
+// diagonal costs are taken from the layers, which must agree on them.
+// Layers that wrap around their edges cannot be stacked.
+type Stack struct {
+	width, height int
+	layers        []*Graph
+	links         []Link
+	stairs        []Stairs
+	elevators     []Elevator
+
+	vertical     bool
+	verticalCost float64
+	diagonal     bool
+	diagonalSet  bool
+
+	start, goal       Point3
+	hasStart, hasGoal bool
+
+	flat *Graph // graph of the most recent solve
+}
+
+// NewStack returns depth empty width x height layers.
+func NewStack(width, height, depth int) *Stack {
+	if depth < 0 {
+		panic("dijkstrapf: negative stack depth")
+	}
+	s := &Stack{width: width, height: height}
+	for z := 0; z < depth; z++ {
+		s.layers = append(s.layers, NewGraph(width, height))
+	}
+	return s
+}
+
+// Depth returns the number of layers.
+func (s *Stack) Depth() int { return len(s.layers) }
+
+// Layer returns layer z for editing walls and weights.
+func (s *Stack) Layer(z int) *Graph { return s.layers[z] }
+
+// InBounds reports whether p lies inside the stack.
+func (s *Stack) InBounds(p Point3) bool {
+	return p.Z >= 0 && p.Z < len(s.layers) && s.layers[p.Z].InBounds(Point{p.X, p.Y})
+}
+
+// SetDiagonal enables or disables diagonal moves within every layer,
+// whatever the layers themselves allow.
+func (s *Stack) SetDiagonal(on bool) { s.diagonal, s.diagonalSet = on, true }
+
+// SetVerticalMoves lets every walkable cell connect straight up and down to
+// the walkable cell above and below it at the given cost, as in voxel
+// worlds. A cost of zero or less disables vertical moves again.
+func (s *Stack) SetVerticalMoves(cost float64) {
+	s.vertical = cost > 0
+	s.verticalCost = cost
+}
+
+// Connect adds a two-way link between a and b, such as a staircase.
+func (s *Stack) Connect(a, b Point3, cost float64) error {
+	return s.AddLink(Link{From: a, To: b, Cost: cost})
+}
+
+// AddLink adds l to the stack.
+func (s *Stack) AddLink(l Link) error {
+	if !s.InBounds(l.From) || !s.InBounds(l.To) {
+		return ErrOutOfBounds
+	}
+	if l.Cost < 0 || math.IsNaN(l.Cost) {
+		return ErrNegativeCost
+	}
+	s.links = append(s.links, l)
+	return nil
+}
+
+// Links returns the links added to the stack.
+func (s *Stack) Links() []Link { return append([]Link(nil), s.links...) }
+
+// SetStart sets the start cell.
+func (s *Stack) SetStart(p Point3) error {
+	if !s.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	s.start, s.hasStart = p, true
+	return nil
+}
+
+// SetGoal sets the goal cell.
+func (s *Stack) SetGoal(p Point3) error {
+	if !s.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	s.goal, s.hasGoal = p, true
+	return nil
+}
+
+// FindPath runs Dijkstra's algorithm from the start to the goal cell.
+func (s *Stack) FindPath(opts ...Option) (Path3, error) {
+	return s.Solve("dijkstra", opts...)
+}
+
+// Solve runs the solver registered under algo on the stack. Cost functions
+// and heuristics passed as options see flattened coordinates and are
+// therefore rejected; the stack supplies its own admissible heuristic.
+func (s *Stack) Solve(algo string, opts ...Option) (Path3, error) {
+	if !s.hasStart {
+		return Path3{}, ErrNoStart
+	}
+	if !s.hasGoal {
+		return Path3{}, ErrNoGoal
+	}
+	if o := buildOptions(opts); o.Cost != nil || o.Heuristic != nil {
+		return Path3{}, fmt.Errorf("%w: cost functions and heuristics on stacks", ErrUnsupported)
+	}
+	g, err := s.flatten()
+	if err != nil {
+		return Path3{}, err
+	}
+	s.flat = g
+	opts = append(opts, WithHeuristic(s.heuristic(g)))
+	p, err := g.Solve(algo, opts...)
+	if err != nil {
+		return Path3{}, err
+	}
+	out := Path3{Cost: p.Cost, Points: make([]Point3, len(p.Points))}
+	for i, fp := range p.Points {
+		out.Points[i] = s.unflatten(fp)
+	}
+	return out, nil
+}
+
+// Stats returns the statistics of the most recent solve.
+func (s *Stack) Stats() Stats {
+	if s.flat == nil {
+		return Stats{}
+	}
+	return s.flat.Stats()
+}
+
+// Layers are flattened into one tall graph, separated by rows of walls so
+// that no grid move crosses from one layer to the next.
+func (s *Stack) flatPoint(p Point3) Point {
+	return Point{p.X, p.Z*(s.height+1) + p.Y}
+}
+
+func (s *Stack) unflatten(p Point) Point3 {
+	return Point3{p.X, p.Y % (s.height + 1), p.Y / (s.height + 1)}
+}
+
+func (s *Stack) flatten() (*Graph, error) {
+	depth := len(s.layers)
+	g := NewGraph(s.width, max(0, depth*(s.height+1)-1))
+	if depth > 0 {
+		// The flat graph moves by one set of rules, so the layers must
+		// agree on them. Wrapping would join the layers to each other.
+		first := s.layers[0]
+		for _, layer := range s.layers {
+			if layer.wrap {
+				return nil, fmt.Errorf("%w: stacking layers that wrap", ErrUnsupported)
+			}
+			if !s.diagonalSet && layer.diagonal != first.diagonal || layer.corners != first.corners || layer.DiagonalCost() != first.DiagonalCost() {
+				return nil, fmt.Errorf("%w: layers with different diagonal moves, corner rules or diagonal costs", ErrUnsupported)
+			}
+		}
+		g.diagonal = first.diagonal
+		g.corners, g.diagonalCost = first.corners, first.diagonalCost
+	}
+	if s.diagonalSet {
+		g.diagonal = s.diagonal
+	}
+	for z, layer := range s.layers {
+		for y := 0; y < s.height; y++ {
+			fy := z*(s.height+1) + y
+			for x := 0; x < s.width; x++ {
+				if layer.gridMatrix[y][x] == Wall {
+					g.gridMatrix[fy][x] = Wall
+				}
+				g.weights[fy][x] = layer.weights[y][x]
+			}
+		}
+		if z < depth-1 {
+			for x := 0; x < s.width; x++ {
+				g.gridMatrix[(z+1)*(s.height+1)-1][x] = Wall
+			}
+		}
+	}
+	for _, l := range s.links {
+		a, b := s.flatPoint(l.From), s.flatPoint(l.To)
+		g.addExtraEdge(a, b, l.Cost)
+		if !l.OneWay {
+			g.addExtraEdge(b, a, l.Cost)
+		}
+	}
+	s.flattenStairs(g)
+	if s.vertical {
+		for z := 0; z+1 < depth; z++ {
+			for y := 0; y < s.height; y++ {
+				for x := 0; x < s.width; x++ {
+					a, b := s.flatPoint(Point3{x, y, z}), s.flatPoint(Point3{x, y, z + 1})
+					g.addExtraEdge(a, b, s.verticalCost)
+					g.addExtraEdge(b, a, s.verticalCost)
+				}
+			}
+		}
+	}
+	if err := g.SetStart(s.flatPoint(s.start)); err != nil {
+		return nil, err
+	}
+	if err := g.SetGoal(s.flatPoint(s.goal)); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// heuristic returns the in-layer grid distance to the goal, which never
+// overestimates as long as links do not jump sideways. Otherwise it gives
+// up and returns zero.
+func (s *Stack) heuristic(g *Graph) HeuristicFunc {
+	for _, l := range s.links {
+		if l.From.X != l.To.X || l.From.Y != l.To.Y {
+			return func(Point, Point) float64 { return 0 }
+		}
+	}
+	w := g.minWeight()
+	return func(a, b Point) float64 {
+		pa, pb := s.unflatten(a), s.unflatten(b)
+		return w * g.gridDistance(abs(pa.X-pb.X), abs(pa.Y-pb.Y))
+	}
+}
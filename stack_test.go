@@ -0,0 +1,98 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestStackSingleLayer(t *testing.T) {
+	s := dijkstrapf.NewStack(6, 5, 1)
+	layer := s.Layer(0)
+	layer.SetDiagonal(true)
+	layer.SetCornerRule(dijkstrapf.CornerNever)
+	if err := layer.SetDiagonalCost(math.Sqrt2); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []dijkstrapf.Point{{X: 2, Y: 1}, {X: 1, Y: 2}, {X: 3, Y: 3}, {X: 4, Y: 2}} {
+		layer.SetWall(w, true)
+	}
+	start, goal := dijkstrapf.Point{X: 0, Y: 0}, dijkstrapf.Point{X: 5, Y: 4}
+	if err := layer.SetStart(start); err != nil {
+		t.Fatal(err)
+	}
+	if err := layer.SetGoal(goal); err != nil {
+		t.Fatal(err)
+	}
+	want, err := layer.FindPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetStart(dijkstrapf.Point3{X: start.X, Y: start.Y})
+	s.SetGoal(dijkstrapf.Point3{X: goal.X, Y: goal.Y})
+	got, err := s.FindPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var points []dijkstrapf.Point
+	for _, p := range got.Points {
+		points = append(points, dijkstrapf.Point{X: p.X, Y: p.Y})
+	}
+	if got.Cost != want.Cost || !reflect.DeepEqual(points, want.Points) {
+		t.Fatalf("stack gives %v cost %g, layer alone %v cost %g", points, got.Cost, want.Points, want.Cost)
+	}
+}
+
+func TestStackUnsupportedLayers(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(s *dijkstrapf.Stack)
+	}{
+		{"corner rules", func(s *dijkstrapf.Stack) { s.Layer(1).SetCornerRule(dijkstrapf.CornerNever) }},
+		{"diagonal moves", func(s *dijkstrapf.Stack) { s.Layer(0).SetDiagonal(true) }},
+		{"wrap", func(s *dijkstrapf.Stack) { s.Layer(0).SetWrap(true); s.Layer(1).SetWrap(true) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := dijkstrapf.NewStack(3, 3, 2)
+			tt.edit(s)
+			s.SetStart(dijkstrapf.Point3{})
+			s.SetGoal(dijkstrapf.Point3{X: 2, Y: 2})
+			if _, err := s.FindPath(); !errors.Is(err, dijkstrapf.ErrUnsupported) {
+				t.Fatalf("err = %v, want ErrUnsupported", err)
+			}
+		})
+	}
+}
+
+func TestStackSetDiagonalOverridesLayers(t *testing.T) {
+	tests := []struct {
+		name            string
+		layers, stacked bool
+		cost            float64
+	}{
+		{"off over diagonal layers", true, false, 8},
+		{"on over orthogonal layers", false, true, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := dijkstrapf.NewStack(5, 5, 2)
+			s.Layer(0).SetDiagonal(tt.layers)
+			s.Layer(1).SetDiagonal(tt.layers)
+			s.SetDiagonal(tt.stacked)
+			s.SetStart(dijkstrapf.Point3{})
+			s.SetGoal(dijkstrapf.Point3{X: 4, Y: 4})
+			p, err := s.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Cost != tt.cost {
+				t.Fatalf("cost %g, want %g", p.Cost, tt.cost)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrBadElevator is returned by AddElevator for elevators serving fewer
+// than two floors.
+var ErrBadElevator = errors.New("dijkstrapf: elevator must serve two or more floors")
+
+// Stairs connect a cell of a stack with the cell straight above it, both
+// ways.
+type Stairs struct {
+	// At is the foot of the stairs; the top is on layer At.Z+1.
+	At Point3
+	// Cost is the full cost of climbing or descending them.
+	Cost float64
+}
+
+// Elevator is a shaft at one cell of a stack that connects every pair of
+// the floors it serves.
+type Elevator struct {
+	X, Y   int
+	Floors []int
+	// Wait is the cost of calling the elevator, paid once per ride.
+	Wait float64
+	// PerFloor is the cost of travelling one floor up or down.
+	PerFloor float64
+}
+
+// rideCost returns the cost of riding from floor a to floor b.
+func (e Elevator) rideCost(a, b int) float64 {
+	return e.Wait + e.PerFloor*float64(abs(a-b))
+}
+
+// serves reports whether e stops at floor z.
+func (e Elevator) serves(z int) bool {
+	for _, f := range e.Floors {
+		if f == z {
+			return true
+		}
+	}
+	return false
+}
+
+// AddStairs adds stairs from at to the cell above it.
+func (s *Stack) AddStairs(at Point3, cost float64) error {
+	if !s.InBounds(at) || !s.InBounds(Point3{at.X, at.Y, at.Z + 1}) {
+		return ErrOutOfBounds
+	}
+	if cost < 0 || math.IsNaN(cost) {
+		return ErrNegativeCost
+	}
+	s.stairs = append(s.stairs, Stairs{at, cost})
+	return nil
+}
+
+// AddElevator adds e to the stack. Riding it from one floor to another is
+// a single move costing e.Wait plus e.PerFloor for every floor travelled,
+// so one elevator replaces a link between every pair of its floors. Its
+// cell must be walkable on a floor for it to stop there.
+func (s *Stack) AddElevator(e Elevator) error {
+	floors := make(map[int]bool)
+	for _, z := range e.Floors {
+		if !s.InBounds(Point3{e.X, e.Y, z}) {
+			return ErrOutOfBounds
+		}
+		floors[z] = true
+	}
+	if len(floors) < 2 {
+		return ErrBadElevator
+	}
+	if e.Wait < 0 || e.PerFloor < 0 || math.IsNaN(e.Wait) || math.IsNaN(e.PerFloor) {
+		return ErrNegativeCost
+	}
+	e.Floors = append([]int(nil), e.Floors...)
+	s.elevators = append(s.elevators, e)
+	return nil
+}
+
+// Stairs returns the stairs added to the stack.
+func (s *Stack) Stairs() []Stairs { return append([]Stairs(nil), s.stairs...) }
+
+// Elevators returns the elevators added to the stack.
+func (s *Stack) Elevators() []Elevator { return append([]Elevator(nil), s.elevators...) }
+
+// flattenStairs adds the moves of the stairs and elevators to the
+// flattened graph g.
+func (s *Stack) flattenStairs(g *Graph) {
+	for _, st := range s.stairs {
+		a, b := s.flatPoint(st.At), s.flatPoint(Point3{st.At.X, st.At.Y, st.At.Z + 1})
+		g.addExtraEdge(a, b, st.Cost)
+		g.addExtraEdge(b, a, st.Cost)
+	}
+	for _, e := range s.elevators {
+		for _, za := range e.Floors {
+			for _, zb := range e.Floors {
+				if za != zb {
+					a, b := s.flatPoint(Point3{e.X, e.Y, za}), s.flatPoint(Point3{e.X, e.Y, zb})
+					g.addExtraEdge(a, b, e.rideCost(za, zb))
+				}
+			}
+		}
+	}
+}
+
+// MoveKind says how a path moves from one cell of a stack to the next.
+type MoveKind int
+
+const (
+	// MoveWalk is a step to a neighbouring cell of the same layer.
+	MoveWalk MoveKind = iota
+	// MoveLink takes a link added with AddLink or Connect.
+	MoveLink
+	// MoveVertical is a vertical move enabled by SetVerticalMoves.
+	MoveVertical
+	// MoveStairs climbs or descends stairs.
+	MoveStairs
+	// MoveElevator rides an elevator.
+	MoveElevator
+)
+
+var moveKindNames = []string{"walk", "link", "vertical", "stairs", "elevator"}
+
+func (k MoveKind) String() string {
+	if k >= 0 && int(k) < len(moveKindNames) {
+		return moveKindNames[k]
+	}
+	return fmt.Sprintf("MoveKind(%d)", int(k))
+}
+
+// Moves returns how p moves at each of its steps, for turning a path into
+// directions such as "take the elevator to floor 3". Where several ways
+// connect the same two cells, stairs are named before elevators, elevators
+// before links and links before vertical moves.
+func (s *Stack) Moves(p Path3) []MoveKind {
+	var out []MoveKind
+	for i := 1; i < len(p.Points); i++ {
+		out = append(out, s.moveKind(p.Points[i-1], p.Points[i]))
+	}
+	return out
+}
+
+func (s *Stack) moveKind(a, b Point3) MoveKind {
+	if a.X == b.X && a.Y == b.Y && a.Z != b.Z {
+		lo := min(a.Z, b.Z)
+		for _, st := range s.stairs {
+			if st.At == (Point3{a.X, a.Y, lo}) && abs(a.Z-b.Z) == 1 {
+				return MoveStairs
+			}
+		}
+		for _, e := range s.elevators {
+			if e.X == a.X && e.Y == a.Y && e.serves(a.Z) && e.serves(b.Z) {
+				return MoveElevator
+			}
+		}
+	}
+	for _, l := range s.links {
+		if l.From == a && l.To == b || !l.OneWay && l.From == b && l.To == a {
+			return MoveLink
+		}
+	}
+	if a.Z != b.Z {
+		return MoveVertical
+	}
+	return MoveWalk
+}
@@ -0,0 +1,70 @@
+package dijkstrapf
+
+import (
+	"math"
+	"time"
+)
+
+// Stats describes the work done by the most recent solve on a graph.
+type Stats struct {
+	// Expanded counts the nodes taken off the frontier.
+	Expanded int
+	// Duration is the wall time spent in the solver.
+	Duration time.Duration
+}
+
+// Stats returns the statistics of the most recent solve.
+func (g *Graph) Stats() Stats { return g.stats }
+
+// Explored reports whether the most recent solve expanded p.
+func (g *Graph) Explored(p Point) bool {
+	return g.InBounds(p) && g.closed != nil && g.closed[g.id(p)]
+}
+
+// Distance returns the distance from the start to p found by the most
+// recent solve, and whether p was settled. Use WithFullMap to settle every
+// reachable cell.
+func (g *Graph) Distance(p Point) (float64, bool) {
+	if !g.Explored(p) {
+		return math.Inf(1), false
+	}
+	return g.dist[g.id(p)], true
+}
+
+// run times s, the solver called name, and records its statistics on g.
+// It switches to IDA* or fails if s would exceed the memory limit.
+func (g *Graph) run(name string, s Solver, opts []Option) (Path, error) {
+	o := buildOptions(opts)
+	parent, solve := rootSpan(o).start("dijkstrapf.solve")
+	defer solve.End()
+	g.setGridAttributes(solve)
+
+	_, prep := parent.start("dijkstrapf.preprocess")
+	if err := g.checkMemory(o); err != nil {
+		if !o.MemoryFallback {
+			prep.End()
+			solve.SetAttribute("dijkstrapf.algorithm", name)
+			solve.SetAttribute("dijkstrapf.error", err.Error())
+			return Path{}, err
+		}
+		name, s = "idastar", idastarFallback(err)
+	}
+	solve.SetAttribute("dijkstrapf.algorithm", name)
+	g.closed, g.stats = nil, Stats{}
+	begin := time.Now()
+	if o.Tracer != nil {
+		// Build the adjacency up front, so that its time is told apart
+		// from the search's.
+		g.adjacency()
+	}
+	prep.End()
+
+	var search Span
+	g.tracing, search = parent.start("dijkstrapf.search")
+	path, err := s(g, o)
+	g.tracing = spanParent{}
+	search.End()
+	g.stats.Duration = time.Since(begin)
+	g.setOutcomeAttributes(solve, path, err)
+	return path, err
+}
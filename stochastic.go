@@ -0,0 +1,40 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// FindPathRobust finds a path from the start to the goal when step costs
+// are random: the usual cost of a step, honouring opts, is its mean, and
+// variance returns its variance. Step costs are taken to be independent,
+// so a path's mean and variance are the sums over its steps. The path
+// minimises its mean plus k times its standard deviation: k = 0 asks for
+// the least expected cost, larger k for paths that are more reliably
+// cheap, such as a commute that must not run late.
+//
+// FindPathRobust returns the path, whose Cost is its mean, and the value
+// of the objective. For k > 0 the objective does not add up step by step;
+// it grows with both the mean and the variance, so the best path is among
+// the Pareto-optimal ones FindPareto returns for the two, and that is how
+// it is found, with the same caveat about their number.
+func (g *Graph) FindPathRobust(variance CostFunc, k float64, opts ...Option) (Path, float64, error) {
+	if !(k >= 0) {
+		return Path{}, 0, fmt.Errorf("%w: robustness %g is negative", ErrBadWeight, k)
+	}
+	if k == 0 {
+		p, err := g.FindPath(opts...)
+		return p, p.Cost, err
+	}
+	front, err := g.FindPareto(variance, opts...)
+	if err != nil {
+		return Path{}, 0, err
+	}
+	best, value := 0, math.Inf(1)
+	for i, pp := range front {
+		if v := pp.Costs[0] + k*math.Sqrt(pp.Costs[1]); v < value {
+			best, value = i, v
+		}
+	}
+	return Path{Points: front[best].Points, Cost: front[best].Costs[0]}, value, nil
+}
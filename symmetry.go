@@ -0,0 +1,173 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Symmetry is a symmetry of generated grids, for competitive maps where
+// every player must get the same chances.
+type Symmetry int
+
+const (
+	// SymmetryNone leaves the grid as generated.
+	SymmetryNone Symmetry = iota
+	// SymmetryHorizontal mirrors the left half of the grid onto the right.
+	SymmetryHorizontal
+	// SymmetryVertical mirrors the top half of the grid onto the bottom.
+	SymmetryVertical
+	// SymmetryRotational turns the grid half a turn onto itself.
+	SymmetryRotational
+)
+
+var symmetryNames = []string{"none", "horizontal", "vertical", "rotational"}
+
+func (s Symmetry) String() string {
+	if s >= 0 && int(s) < len(symmetryNames) {
+		return symmetryNames[s]
+	}
+	return fmt.Sprintf("Symmetry(%d)", int(s))
+}
+
+// image returns the cell p maps to under s in a width x height grid.
+func (s Symmetry) image(p Point, width, height int) Point {
+	switch s {
+	case SymmetryHorizontal:
+		return Point{width - 1 - p.X, p.Y}
+	case SymmetryVertical:
+		return Point{p.X, height - 1 - p.Y}
+	case SymmetryRotational:
+		return Point{width - 1 - p.X, height - 1 - p.Y}
+	}
+	return p
+}
+
+// GenerateRandomSymmetric is GenerateRandom made symmetric under sym: the
+// walls of one half are mirrored onto the other, and the goal is the image
+// of the start under sym, so (width-1, 0) for SymmetryHorizontal and
+// (0, height-1) for SymmetryVertical.
+func GenerateRandomSymmetric(width, height int, density float64, sym Symmetry, seed int64) *Graph {
+	g := GenerateRandom(width, height, density, seed)
+	g.symmetrize(sym)
+	return g
+}
+
+// GenerateMazeSymmetric is GenerateMaze made symmetric under sym, with the
+// goal placed as by GenerateRandomSymmetric. The two mirrored halves are
+// joined, and any cells left cut off are joined on, by knocking out pairs
+// of mirrored walls, so every open cell can be reached but the maze is no
+// longer perfect.
+func GenerateMazeSymmetric(width, height int, sym Symmetry, seed int64) *Graph {
+	r := rand.New(rand.NewSource(seed))
+	g := GenerateMazeFrom(width, height, r)
+	if sym != SymmetryNone {
+		g.symmetrize(sym)
+		g.joinRegions(sym, r)
+	}
+	return g
+}
+
+// symmetrize copies the walls of the first cell of every pair of cells
+// that sym swaps onto the second, and moves the goal to the image of the
+// start.
+func (g *Graph) symmetrize(sym Symmetry) {
+	if sym == SymmetryNone || !g.hasStart {
+		return
+	}
+	if g.hasGoal {
+		g.gridMatrix[g.goal.Y][g.goal.X] = Empty
+		g.hasGoal = false
+	}
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			q := sym.image(p, g.width, g.height)
+			if g.id(q) <= g.id(p) {
+				continue
+			}
+			switch {
+			case g.gridMatrix[y][x] == Wall:
+				g.gridMatrix[q.Y][q.X] = Wall
+			case g.gridMatrix[q.Y][q.X] == Wall:
+				g.gridMatrix[q.Y][q.X] = Empty
+			}
+		}
+	}
+	g.stale = true
+	g.SetGoal(sym.image(g.start, g.width, g.height))
+}
+
+// joinRegions knocks out walls, each together with its image under sym,
+// until every open cell is connected to the start by orthogonal moves. It
+// prefers walls between two regions, and otherwise digs from the start's
+// region towards the nearest cut-off cell.
+func (g *Graph) joinRegions(sym Symmetry, r *rand.Rand) {
+	open := func(p Point) bool { return g.InBounds(p) && g.gridMatrix[p.Y][p.X] != Wall }
+	for {
+		region := make([]bool, g.nodeCount())
+		region[g.id(g.start)] = true
+		for queue := []Point{g.start}; len(queue) > 0; {
+			c := queue[0]
+			queue = queue[1:]
+			for _, d := range orthogonal {
+				n := Point{c.X + d.X, c.Y + d.Y}
+				if open(n) && !region[g.id(n)] {
+					region[g.id(n)] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+		var cut []Point
+		for y := 0; y < g.height; y++ {
+			for x := 0; x < g.width; x++ {
+				if p := (Point{x, y}); open(p) && !region[g.id(p)] {
+					cut = append(cut, p)
+				}
+			}
+		}
+		if len(cut) == 0 {
+			return
+		}
+
+		var joins, digs []Point
+		for y := 0; y < g.height; y++ {
+			for x := 0; x < g.width; x++ {
+				w := Point{x, y}
+				if open(w) {
+					continue
+				}
+				inside, outside := false, false
+				for _, d := range orthogonal {
+					n := Point{x + d.X, y + d.Y}
+					if open(n) {
+						inside = inside || region[g.id(n)]
+						outside = outside || !region[g.id(n)]
+					}
+				}
+				switch {
+				case inside && outside:
+					joins = append(joins, w)
+				case inside:
+					digs = append(digs, w)
+				}
+			}
+		}
+		var w Point
+		if len(joins) > 0 {
+			w = joins[r.Intn(len(joins))]
+		} else {
+			near := -1
+			for _, c := range digs {
+				for _, p := range cut {
+					if d := abs(c.X-p.X) + abs(c.Y-p.Y); near < 0 || d < near {
+						w, near = c, d
+					}
+				}
+			}
+		}
+		for _, p := range []Point{w, sym.image(w, g.width, g.height)} {
+			g.gridMatrix[p.Y][p.X] = Empty
+		}
+		g.stale = true
+	}
+}
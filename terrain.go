@@ -0,0 +1,195 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrUnknownTerrain is returned for terrain names the graph does not know.
+var ErrUnknownTerrain = errors.New("dijkstrapf: unknown terrain")
+
+// Terrain is a named kind of ground with a movement cost.
+type Terrain struct {
+	Name string
+	// Cost is the weight of a cell of this terrain; +Inf makes it
+	// impassable.
+	Cost float64
+	// Symbol is used in map files and text renderings.
+	Symbol byte
+	// Color is an ANSI SGR parameter such as "32" (green) used by colored
+	// renderings.
+	Color string
+}
+
+// Impassable is the cost of terrain that cannot be entered.
+var Impassable = math.Inf(1)
+
+// DefaultTerrains are defined on every new graph.
+var DefaultTerrains = []Terrain{
+	{Name: "road", Cost: 1, Symbol: '=', Color: "37"},
+	{Name: "grass", Cost: 2, Symbol: ',', Color: "32"},
+	{Name: "swamp", Cost: 5, Symbol: '%', Color: "33"},
+	{Name: "water", Cost: Impassable, Symbol: '~', Color: "34"},
+}
+
+// DefineTerrain adds t to the graph's terrain table, or replaces the
+// terrain of the same name and re-prices every cell that uses it.
+func (g *Graph) DefineTerrain(t Terrain) error {
+	if !(t.Cost > 0) {
+		return ErrBadWeight
+	}
+	switch c := t.Symbol; {
+	case c == SymbolEmpty, c == SymbolWall, c == SymbolStart, c == SymbolGoal,
+		c >= '0' && c <= '9', c <= ' ', c > '~':
+		return fmt.Errorf("dijkstrapf: terrain symbol %q is reserved", c)
+	}
+	for i := range g.terrains {
+		if g.terrains[i].Name != t.Name && g.terrains[i].Symbol == t.Symbol {
+			return fmt.Errorf("dijkstrapf: terrain symbol %q already used by %s", t.Symbol, g.terrains[i].Name)
+		}
+	}
+	for i := range g.terrains {
+		if g.terrains[i].Name == t.Name {
+			g.terrains[i] = t
+			g.repriceTerrain(i)
+			return nil
+		}
+	}
+	g.terrains = append(g.terrains, t)
+	return nil
+}
+
+// Terrains returns the graph's terrain table.
+func (g *Graph) Terrains() []Terrain {
+	return append([]Terrain(nil), g.terrains...)
+}
+
+// SetTerrain gives p the named terrain and its cost. Walls are cleared.
+func (g *Graph) SetTerrain(p Point, name string) error {
+	if !g.InBounds(p) {
+		return ErrOutOfBounds
+	}
+	i := g.terrainIndex(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrUnknownTerrain, name)
+	}
+	if g.gridMatrix[p.Y][p.X] == Wall {
+		g.gridMatrix[p.Y][p.X] = Empty
+	}
+	if g.terrainAt == nil {
+		g.terrainAt = make([][]uint8, g.height)
+		for y := range g.terrainAt {
+			g.terrainAt[y] = make([]uint8, g.width)
+		}
+	}
+	g.terrainAt[p.Y][p.X] = uint8(i + 1)
+	g.weights[p.Y][p.X] = g.terrains[i].Cost
+	g.stale = true
+	g.changed(ChangeTerrain, p)
+	return nil
+}
+
+// TerrainAt returns the terrain of p, if it has one.
+func (g *Graph) TerrainAt(p Point) (Terrain, bool) {
+	i := g.terrainOf(p)
+	if i < 0 {
+		return Terrain{}, false
+	}
+	return g.terrains[i], true
+}
+
+func (g *Graph) terrainIndex(name string) int {
+	for i, t := range g.terrains {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *Graph) terrainBySymbol(c byte) int {
+	for i, t := range g.terrains {
+		if t.Symbol == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// terrainOf returns the terrain index of p, or -1.
+func (g *Graph) terrainOf(p Point) int {
+	if g.terrainAt == nil || !g.InBounds(p) {
+		return -1
+	}
+	return int(g.terrainAt[p.Y][p.X]) - 1
+}
+
+// clearTerrain removes the terrain of p after its weight or wall state was
+// set directly.
+func (g *Graph) clearTerrain(p Point) {
+	if g.terrainAt != nil {
+		g.terrainAt[p.Y][p.X] = 0
+	}
+}
+
+func (g *Graph) repriceTerrain(i int) {
+	for y, row := range g.terrainAt {
+		for x, t := range row {
+			if int(t) == i+1 {
+				g.weights[y][x] = g.terrains[i].Cost
+				g.changed(ChangeWeight, Point{x, y})
+			}
+		}
+	}
+	g.stale = true
+}
+
+// WriteTerrain draws the grid using terrain symbols, '#' for walls, 'S' and
+// 'G' for the endpoints and the map-file symbols for everything else. With
+// color set, terrain symbols are wrapped in their ANSI colors.
+func (g *Graph) WriteTerrain(w io.Writer, color bool) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			i := g.terrainOf(p)
+			if i < 0 || g.gridMatrix[y][x] != Empty {
+				c, err := g.symbol(p)
+				if err != nil {
+					c = '?'
+				}
+				bw.WriteByte(c)
+				continue
+			}
+			writeColored(bw, g.terrains[i].Symbol, g.terrains[i].Color, color)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WriteLegend lists the symbols used by WriteTerrain and what they cost.
+func (g *Graph) WriteLegend(w io.Writer, color bool) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range g.terrains {
+		writeColored(bw, t.Symbol, t.Color, color)
+		if math.IsInf(t.Cost, 1) {
+			fmt.Fprintf(bw, " %s (impassable)\n", t.Name)
+		} else {
+			fmt.Fprintf(bw, " %s (cost %g)\n", t.Name, t.Cost)
+		}
+	}
+	fmt.Fprintf(bw, "%c wall\n%c start\n%c goal\n", SymbolWall, SymbolStart, SymbolGoal)
+	return bw.Flush()
+}
+
+func writeColored(w *bufio.Writer, c byte, sgr string, color bool) {
+	if !color || sgr == "" {
+		w.WriteByte(c)
+		return
+	}
+	fmt.Fprintf(w, "\x1b[%sm%c\x1b[0m", sgr, c)
+}
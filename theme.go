@@ -0,0 +1,104 @@
+package dijkstrapf
+
+// Theme picks the ANSI colors of a colored rendering. Every color is an SGR
+// parameter such as "32" (green) or "38;5;208" (256-color orange); an empty
+// color draws the symbol plainly.
+type Theme struct {
+	Name string
+	// Wall, Start, Goal, Empty and Weight color walls, the endpoints,
+	// cells of weight 1 and other weighted cells.
+	Wall, Start, Goal, Empty, Weight string
+	// PathA and PathB color cells on only the first or the second of two
+	// overlaid paths, PathBoth cells on both.
+	PathA, PathB, PathBoth string
+	// Terrain maps terrain names to colors, overriding Terrain.Color.
+	Terrain map[string]string
+}
+
+var (
+	// ThemeDefault uses the terrains' own colors and marks the endpoints
+	// in green and red.
+	ThemeDefault = Theme{
+		Name:     "default",
+		Start:    "1;32",
+		Goal:     "1;31",
+		PathA:    "1;36",
+		PathB:    "1;35",
+		PathBoth: "1;33",
+	}
+	// ThemeHighContrast draws walls in reverse video and everything else
+	// in bold, bright colors.
+	ThemeHighContrast = Theme{
+		Name:     "high-contrast",
+		Wall:     "7",
+		Start:    "1;30;103",
+		Goal:     "1;30;106",
+		Weight:   "1;97",
+		PathA:    "1;30;107",
+		PathB:    "1;30;105",
+		PathBoth: "1;30;102",
+		Terrain: map[string]string{
+			"road":  "1;97",
+			"grass": "1;93",
+			"swamp": "1;95",
+			"water": "1;96",
+		},
+	}
+	// ThemeDeuteranopia avoids telling red from green, using blues and
+	// oranges that stay distinct with the common forms of color blindness.
+	ThemeDeuteranopia = Theme{
+		Name:     "deuteranopia",
+		Start:    "1;38;5;33",
+		Goal:     "1;38;5;208",
+		Weight:   "38;5;180",
+		PathA:    "1;38;5;39",
+		PathB:    "1;38;5;214",
+		PathBoth: "1;97",
+		Terrain: map[string]string{
+			"road":  "37",
+			"grass": "38;5;110",
+			"swamp": "38;5;178",
+			"water": "38;5;25",
+		},
+	}
+)
+
+// Themes lists the built-in themes.
+var Themes = []Theme{ThemeDefault, ThemeHighContrast, ThemeDeuteranopia}
+
+// colorOf returns the color of the cell p drawn as c.
+func (t *Theme) colorOf(g *Graph, p Point, c byte) string {
+	switch g.gridMatrix[p.Y][p.X] {
+	case Wall:
+		return t.Wall
+	case Start:
+		return t.Start
+	case Goal:
+		return t.Goal
+	}
+	if i := g.terrainOf(p); i >= 0 && g.terrains[i].Symbol == c {
+		return t.terrainColor(g.terrains[i])
+	}
+	if c == SymbolEmpty {
+		return t.Empty
+	}
+	return t.Weight
+}
+
+func (t *Theme) terrainColor(tr Terrain) string {
+	if sgr, ok := t.Terrain[tr.Name]; ok {
+		return sgr
+	}
+	return tr.Color
+}
+
+// markColor returns the color of an overlay mark.
+func (t *Theme) markColor(m byte) string {
+	switch m {
+	case SymbolPathA:
+		return t.PathA
+	case SymbolPathB:
+		return t.PathB
+	}
+	return t.PathBoth
+}
@@ -0,0 +1,131 @@
+package dijkstrapf
+
+import "math/rand"
+
+// TieBreak decides which of several equally good frontier cells Dijkstra's
+// algorithm and A* expand first, and so which of several equally short
+// paths they return. The zero value leaves the order to the queue.
+type TieBreak struct {
+	kind tieKind
+	seed int64
+	rng  *rand.Rand
+}
+
+type tieKind int
+
+const (
+	tieQueue tieKind = iota
+	tieRowMajor
+	tieStraight
+	tieRandom
+)
+
+var (
+	// TieRowMajor expands the top-most, then left-most cell first.
+	TieRowMajor = TieBreak{kind: tieRowMajor}
+	// TieStraight prefers cells reached without changing direction, which
+	// gives paths with fewer turns. Among equally straight cells it falls
+	// back to row-major order.
+	TieStraight = TieBreak{kind: tieStraight}
+)
+
+// TieRandom breaks ties at random, reproducibly for a given seed.
+func TieRandom(seed int64) TieBreak {
+	return TieBreak{kind: tieRandom, seed: seed}
+}
+
+// TieRand breaks ties with numbers drawn from r, so that a series of solves
+// sharing r is reproducible as a whole.
+func TieRand(r *rand.Rand) TieBreak {
+	return TieBreak{kind: tieRandom, rng: r}
+}
+
+// WithTieBreak makes Dijkstra's algorithm and A* break ties between equal
+// priorities with t, so that the path returned among several of the same
+// cost is reproducible. A policy other than the zero TieBreak uses its own
+// binary heap and overrides WithQueue.
+func WithTieBreak(t TieBreak) Option {
+	return func(o *Options) { o.TieBreak = t }
+}
+
+// frontier returns the queue a solver should use for n nodes. prev is the
+// solver's predecessor array, which TieStraight consults; entries must be
+// up to date before a node is pushed or its key decreased.
+func (g *Graph) frontier(o *Options, n int, prev []int) PriorityQueue {
+	var key func(node int) float64
+	switch o.TieBreak.kind {
+	case tieRowMajor:
+		key = func(node int) float64 { return float64(node) }
+	case tieStraight:
+		key = func(node int) float64 {
+			if g.turns(prev, node) {
+				return float64(n + node)
+			}
+			return float64(node)
+		}
+	case tieRandom:
+		r := o.TieBreak.rng
+		if r == nil {
+			r = rand.New(rand.NewSource(o.TieBreak.seed))
+		}
+		key = func(int) float64 { return r.Float64() }
+	default:
+		return o.newQueue(n)
+	}
+	h := &tieHeap{BinaryHeap: NewBinaryHeap(n), key: key}
+	h.tie = make([]float64, n)
+	return h
+}
+
+// turns reports whether reaching node from prev[node] changes direction.
+func (g *Graph) turns(prev []int, node int) bool {
+	p := prev[node]
+	if p < 0 || prev[p] < 0 {
+		return false
+	}
+	a, b, c := g.point(prev[p]), g.point(p), g.point(node)
+	return b.X-a.X != c.X-b.X || b.Y-a.Y != c.Y-b.Y
+}
+
+// preferParent reports whether cur should replace the current predecessor
+// of node when both reach it at the same cost.
+func (g *Graph) preferParent(o *Options, prev []int, node, cur int) bool {
+	if o.TieBreak.kind != tieStraight || prev[node] < 0 || !g.turns(prev, node) {
+		return false
+	}
+	old := prev[node]
+	prev[node] = cur
+	straight := !g.turns(prev, node)
+	prev[node] = old
+	return straight
+}
+
+// tieHeap is a binary heap ordered by priority and then by a secondary key
+// computed when a node is pushed or its key decreased.
+type tieHeap struct {
+	*BinaryHeap
+	key func(node int) float64
+}
+
+func (h *tieHeap) Push(node int, priority float64) {
+	for node >= len(h.tie) {
+		h.tie = append(h.tie, 0)
+	}
+	h.tie[node] = h.key(node)
+	h.BinaryHeap.Push(node, priority)
+}
+
+// DecreaseKey also accepts an unchanged priority if the secondary key
+// improves.
+func (h *tieHeap) DecreaseKey(node int, priority float64) {
+	i := h.pos[node]
+	if i < 0 {
+		return
+	}
+	t := h.key(node)
+	if priority > h.prio[i] || priority == h.prio[i] && t >= h.tie[node] {
+		return
+	}
+	h.prio[i], h.tie[node] = priority, t
+	h.up(i)
+}
@@ -0,0 +1,147 @@
+package dijkstrapf
+
+import (
+	"container/heap"
+	"math"
+)
+
+// TimedConfig describes a world that changes over time for FindPathTimed.
+type TimedConfig struct {
+	// Blocked reports whether p is taken at time t, such as by a moving
+	// obstacle or another agent. The start is at time 0 and every move or
+	// wait takes one time step. nil blocks nothing.
+	Blocked func(p Point, t int) bool
+	// Wait is the cost of waiting in place for one time step. The zero
+	// value makes waiting free, though a path never waits unless that
+	// makes it cheaper. A negative cost forbids waiting.
+	Wait float64
+	// Horizon is the latest time step the search considers; zero means
+	// the number of cells of the grid.
+	Horizon int
+}
+
+// timedState is a node of the time-expanded search.
+type timedState struct {
+	node, t int
+}
+
+// FindPathTimed finds the cheapest path from the start to the goal through
+// a world whose blocked cells change over time. The search runs over pairs
+// of a cell and a time step, so besides moving, an agent may wait in place
+// for cfg.Wait to let an obstacle pass instead of detouring around it.
+// The path has one point per time step, repeating the cell for every wait,
+// so it is not a sequence of moves for ValidatePath. It is guided by the
+// heuristic of the options, as A* is. Only cells are checked against
+// cfg.Blocked; two agents swapping cells in one step are not caught.
+func (g *Graph) FindPathTimed(cfg TimedConfig, opts ...Option) (Path, error) {
+	return g.run("timed", func(g *Graph, o *Options) (Path, error) {
+		if o.Reverse {
+			return Path{}, errForwardOnly
+		}
+		src, dst, err := g.endpoints()
+		if err != nil {
+			return Path{}, err
+		}
+		horizon := cfg.Horizon
+		if horizon <= 0 {
+			horizon = g.nodeCount()
+		}
+		blocked := func(node, t int) bool {
+			return cfg.Blocked != nil && cfg.Blocked(g.point(node), t)
+		}
+		if blocked(src, 0) {
+			return Path{}, ErrNoPath
+		}
+		adj := g.adjacency()
+		h := g.heuristic(o)
+		eps := o.epsilon()
+
+		dist := map[timedState]float64{{src, 0}: 0}
+		prev := make(map[timedState]timedState)
+		pq := &timedQueue{{timedState{src, 0}, eps * h(g.start, g.goal)}}
+		for pq.Len() > 0 {
+			item := heap.Pop(pq).(timedItem)
+			s := item.state
+			d := dist[s]
+			if item.f > d+eps*h(g.point(s.node), g.goal) {
+				continue // stale entry
+			}
+			g.settle(o, s.node, d)
+			if s.node == dst {
+				g.finish(o, dst, d)
+				return Path{Points: timedPoints(g, prev, s, src), Cost: d}, nil
+			}
+			if err := g.cancelled(o); err != nil {
+				return Path{}, err
+			}
+			if s.t == horizon {
+				continue
+			}
+			push := func(next timedState, c float64) {
+				nd := d + c
+				old, ok := dist[next]
+				if ok && nd >= old || blocked(next.node, next.t) {
+					return
+				}
+				if !ok {
+					old = math.Inf(1)
+				}
+				g.relax(o, next.node, old, nd)
+				dist[next], prev[next] = nd, s
+				heap.Push(pq, timedItem{next, nd + eps*h(g.point(next.node), g.goal)})
+			}
+			if cfg.Wait >= 0 {
+				push(timedState{s.node, s.t + 1}, cfg.Wait)
+			}
+			for _, e := range adj[s.node] {
+				c, err := g.stepCost(o, s.node, e)
+				if err != nil {
+					return Path{}, err
+				}
+				push(timedState{e.to, s.t + 1}, c)
+			}
+		}
+		g.finish(o, dst, math.Inf(1))
+		return Path{}, ErrNoPath
+	}, opts)
+}
+
+// timedPoints walks prev back from s to the start at time 0.
+func timedPoints(g *Graph, prev map[timedState]timedState, s timedState, src int) []Point {
+	rev := []Point{g.point(s.node)}
+	for s != (timedState{src, 0}) {
+		s = prev[s]
+		rev = append(rev, g.point(s.node))
+	}
+	for a, b := 0, len(rev)-1; a < b; a, b = a+1, b-1 {
+		rev[a], rev[b] = rev[b], rev[a]
+	}
+	return rev
+}
+
+type timedItem struct {
+	state timedState
+	f     float64
+}
+
+// timedQueue is a min-heap of states by f-value that may hold stale
+// entries; the time-expanded state space is too large to index densely.
+// Ties go to the earlier time, so that free waits do not pad the path.
+type timedQueue []timedItem
+
+func (q timedQueue) Len() int { return len(q) }
+func (q timedQueue) Less(i, j int) bool {
+	if q[i].f != q[j].f {
+		return q[i].f < q[j].f
+	}
+	return q[i].state.t < q[j].state.t
+}
+func (q timedQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *timedQueue) Push(x any)   { *q = append(*q, x.(timedItem)) }
+
+func (q *timedQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,37 @@
+package dijkstrapf_test
+
+import (
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestTimedNoRedundantWaits(t *testing.T) {
+	rows := make([]string, 12)
+	for y := range rows {
+		rows[y] = strings.Repeat(".", 16)
+	}
+	rows[0] = "S" + rows[0][1:]
+	rows[11] = rows[11][:15] + "G"
+	g, err := dijkstrapf.LoadGrid(strings.NewReader(strings.Join(rows, "\n") + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.FindPathAStar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := g.FindPathTimed(dijkstrapf.TimedConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cost != want.Cost || len(got.Points) != len(want.Points) {
+		t.Fatalf("cost %g over %d points, want %g over %d", got.Cost, len(got.Points), want.Cost, len(want.Points))
+	}
+	for i := 1; i < len(got.Points); i++ {
+		if got.Points[i] == got.Points[i-1] {
+			t.Fatalf("waits at %v in step %d with nothing in the way", got.Points[i], i)
+		}
+	}
+}
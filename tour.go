@@ -0,0 +1,193 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxExactStops is the largest number of freely ordered stops that
+// tourOrder solves exactly.
+const maxExactStops = 15
+
+// VisitAll returns the cheapest path from the start to the goal that passes
+// through every waypoint, together with the order in which it visits them
+// as indices into waypoints. For up to 15 waypoints the order is optimal;
+// beyond that it comes from a nearest-neighbour tour improved by segment
+// reversals.
+func (g *Graph) VisitAll(waypoints []Point, opts ...Option) (Path, []int, error) {
+	if _, _, err := g.endpoints(); err != nil {
+		return Path{}, nil, err
+	}
+	stops := append(append([]Point{g.start}, waypoints...), g.goal)
+	legs, err := g.legs(stops, opts)
+	if err != nil {
+		return Path{}, nil, err
+	}
+	order := tourOrder(len(stops), legs.cost, false)
+	visits := make([]int, 0, len(waypoints))
+	for _, s := range order[1 : len(order)-1] {
+		visits = append(visits, s-1)
+	}
+	return legs.join(order), visits, nil
+}
+
+// legTable holds the shortest paths between every ordered pair of stops.
+type legTable struct {
+	n     int
+	paths []Path
+}
+
+// legs solves the shortest path between every ordered pair of stops.
+func (g *Graph) legs(stops []Point, opts []Option) (legTable, error) {
+	n := len(stops)
+	queries := make([]Query, 0, n*n)
+	for _, a := range stops {
+		for _, b := range stops {
+			queries = append(queries, Query{a, b})
+		}
+	}
+	t := legTable{n: n, paths: make([]Path, n*n)}
+	for k, r := range g.SolveMany(queries, opts...) {
+		if r.Err != nil {
+			return legTable{}, fmt.Errorf("%w: from %v to %v", r.Err, queries[k].Start, queries[k].Goal)
+		}
+		t.paths[k] = r.Path
+	}
+	return t, nil
+}
+
+func (t legTable) cost(i, j int) float64 { return t.paths[i*t.n+j].Cost }
+
+// join concatenates the legs between consecutive stops of order.
+func (t legTable) join(order []int) Path {
+	p := Path{Points: t.paths[order[0]*t.n+order[0]].Points}
+	for k := 1; k < len(order); k++ {
+		leg := t.paths[order[k-1]*t.n+order[k]]
+		p.Points = append(p.Points, leg.Points[1:]...)
+		p.Cost += leg.Cost
+	}
+	return p
+}
+
+// tourOrder returns an order of the stops 0..n-1 that starts at 0. A closed
+// tour returns to 0 at the end, which is appended to the order; an open one
+// ends at n-1. The stops in between are ordered exactly by Held-Karp when
+// there are at most maxExactStops of them, heuristically otherwise.
+func tourOrder(n int, cost func(i, j int) float64, closed bool) []int {
+	end := n - 1
+	if closed {
+		end = 0
+	}
+	free := n - 1
+	if !closed {
+		free = n - 2
+	}
+	var inner []int
+	if free <= maxExactStops {
+		inner = heldKarp(free, cost, end)
+	} else {
+		inner = nearestTour(free, cost, end)
+	}
+	return append(append([]int{0}, inner...), end)
+}
+
+// heldKarp orders the free stops 1..k optimally between 0 and end.
+func heldKarp(k int, cost func(i, j int) float64, end int) []int {
+	if k <= 0 {
+		return nil
+	}
+	full := 1<<k - 1
+	// best[mask*k+j] is the cheapest way to leave 0, visit the stops in mask
+	// and stop at stop j+1, which is in mask.
+	best := make([]float64, (full+1)*k)
+	from := make([]int8, (full+1)*k)
+	for i := range best {
+		best[i] = math.Inf(1)
+	}
+	for j := 0; j < k; j++ {
+		best[(1<<j)*k+j] = cost(0, j+1)
+		from[(1<<j)*k+j] = -1
+	}
+	for mask := 1; mask <= full; mask++ {
+		for j := 0; j < k; j++ {
+			cur := best[mask*k+j]
+			if mask&(1<<j) == 0 || math.IsInf(cur, 1) {
+				continue
+			}
+			for nx := 0; nx < k; nx++ {
+				if mask&(1<<nx) != 0 {
+					continue
+				}
+				m := mask | 1<<nx
+				if c := cur + cost(j+1, nx+1); c < best[m*k+nx] {
+					best[m*k+nx] = c
+					from[m*k+nx] = int8(j)
+				}
+			}
+		}
+	}
+	last, total := 0, math.Inf(1)
+	for j := 0; j < k; j++ {
+		if c := best[full*k+j] + cost(j+1, end); c < total {
+			last, total = j, c
+		}
+	}
+	order := make([]int, k)
+	for mask, j, i := full, last, k-1; i >= 0; i-- {
+		order[i] = j + 1
+		prev := int(from[mask*k+j])
+		mask &^= 1 << j
+		j = prev
+	}
+	return order
+}
+
+// nearestTour orders the free stops 1..k between 0 and end with a
+// nearest-neighbour tour improved by 2-opt. Costs may be asymmetric, so the
+// whole tour is re-priced for every candidate reversal.
+func nearestTour(k int, cost func(i, j int) float64, end int) []int {
+	order := make([]int, 0, k)
+	visited := make([]bool, k+1)
+	last := 0
+	for len(order) < k {
+		next := -1
+		for j := 1; j <= k; j++ {
+			if !visited[j] && (next < 0 || cost(last, j) < cost(last, next)) {
+				next = j
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		last = next
+	}
+
+	price := func() float64 {
+		total, at := 0.0, 0
+		for _, s := range order {
+			total += cost(at, s)
+			at = s
+		}
+		return total + cost(at, end)
+	}
+	best := price()
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < k-1; i++ {
+			for j := i + 1; j < k; j++ {
+				reverse(order[i : j+1])
+				if c := price(); c < best-1e-9 {
+					best, improved = c, true
+				} else {
+					reverse(order[i : j+1])
+				}
+			}
+		}
+	}
+	return order
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
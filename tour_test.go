@@ -0,0 +1,207 @@
+package dijkstrapf_test
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+const tourMap = "S...#...\n" +
+	".##.#.#.\n" +
+	".#..#.#.\n" +
+	".#.##.#.\n" +
+	"......#G\n"
+
+// legGraph loads grid with its start moved to a and its goal to b.
+func legGraph(t *testing.T, grid string, a, b dijkstrapf.Point) *dijkstrapf.Graph {
+	t.Helper()
+	g, err := dijkstrapf.LoadGrid(strings.NewReader(grid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetStart(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetGoal(b); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// checkLegs splits p at the stops, in order, and checks that every piece is
+// a valid path as cheap as FindPath between its two stops.
+func checkLegs(t *testing.T, grid string, p dijkstrapf.Path, stops []dijkstrapf.Point) {
+	t.Helper()
+	if len(p.Points) == 0 || p.Points[0] != stops[0] {
+		t.Fatalf("path %v does not start at %v", p.Points, stops[0])
+	}
+	at, total := 0, 0.0
+	for k := 1; k < len(stops); k++ {
+		next := slices.Index(p.Points[at:], stops[k])
+		if next < 0 {
+			t.Fatalf("path %v misses %v after step %d", p.Points, stops[k], at)
+		}
+		next += at
+		if stops[k] == stops[k-1] {
+			continue
+		}
+		g := legGraph(t, grid, stops[k-1], stops[k])
+		want, err := g.FindPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		leg := dijkstrapf.Path{Points: p.Points[at : next+1], Cost: want.Cost}
+		if err := g.ValidatePath(leg); err != nil {
+			t.Fatalf("leg %v to %v: %v", stops[k-1], stops[k], err)
+		}
+		at, total = next, total+want.Cost
+	}
+	if at != len(p.Points)-1 {
+		t.Fatalf("path %v goes on past %v", p.Points, stops[len(stops)-1])
+	}
+	if p.Cost != total {
+		t.Fatalf("cost %g, legs cost %g", p.Cost, total)
+	}
+}
+
+// bruteTour returns the cheapest cost of visiting every stop of middle in
+// any order between first and last, pricing each leg with FindPath.
+func bruteTour(t *testing.T, grid string, first, last dijkstrapf.Point, middle []dijkstrapf.Point) float64 {
+	t.Helper()
+	leg := func(a, b dijkstrapf.Point) float64 {
+		if a == b {
+			return 0
+		}
+		p, err := legGraph(t, grid, a, b).FindPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p.Cost
+	}
+	best := math.Inf(1)
+	var try func(at dijkstrapf.Point, left []dijkstrapf.Point, cost float64)
+	try = func(at dijkstrapf.Point, left []dijkstrapf.Point, cost float64) {
+		if len(left) == 0 {
+			best = min(best, cost+leg(at, last))
+			return
+		}
+		for i, p := range left {
+			rest := append(slices.Clone(left[:i]), left[i+1:]...)
+			try(p, rest, cost+leg(at, p))
+		}
+	}
+	try(first, middle, 0)
+	return best
+}
+
+func TestVisitAll(t *testing.T) {
+	p := func(x, y int) dijkstrapf.Point { return dijkstrapf.Point{X: x, Y: y} }
+	tests := []struct {
+		name      string
+		waypoints []dijkstrapf.Point
+		cost      float64
+		err       error
+	}{
+		{name: "no waypoints", cost: 19},
+		{name: "waypoint on the shortest path", waypoints: []dijkstrapf.Point{p(4, 4)}, cost: 19},
+		{name: "detours", waypoints: []dijkstrapf.Point{p(5, 2), p(3, 0), p(2, 3)}, cost: 21},
+		{name: "goal and a cell on the path", waypoints: []dijkstrapf.Point{p(7, 4), p(5, 3)}, cost: 19},
+		{name: "unreachable", waypoints: []dijkstrapf.Point{p(4, 0)}, err: dijkstrapf.ErrNoPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tourMap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			path, visits, err := g.VisitAll(tt.waypoints)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path.Cost != tt.cost {
+				t.Fatalf("cost %g, want %g", path.Cost, tt.cost)
+			}
+			if err := g.ValidatePath(path); err != nil {
+				t.Fatal(err)
+			}
+			want, err := g.FindPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path.Cost < want.Cost {
+				t.Fatalf("cost %g beats FindPath's %g", path.Cost, want.Cost)
+			}
+			start, _ := g.Start()
+			goal, _ := g.Goal()
+			if best := bruteTour(t, tourMap, start, goal, tt.waypoints); path.Cost != best {
+				t.Fatalf("cost %g, best order costs %g", path.Cost, best)
+			}
+			stops := []dijkstrapf.Point{start}
+			for _, i := range visits {
+				stops = append(stops, tt.waypoints[i])
+			}
+			checkLegs(t, tourMap, path, append(stops, goal))
+		})
+	}
+}
+
+func TestPatrol(t *testing.T) {
+	p := func(x, y int) dijkstrapf.Point { return dijkstrapf.Point{X: x, Y: y} }
+	tests := []struct {
+		name      string
+		waypoints []dijkstrapf.Point
+		cost      float64
+		err       error
+	}{
+		{name: "no waypoints", err: dijkstrapf.ErrNoWaypoints},
+		{name: "one waypoint", waypoints: []dijkstrapf.Point{p(0, 0)}, cost: 0},
+		{name: "there and back", waypoints: []dijkstrapf.Point{p(0, 0), p(7, 4)}, cost: 38},
+		{name: "several", waypoints: []dijkstrapf.Point{p(3, 0), p(7, 1), p(0, 4), p(2, 2)}, cost: 34},
+		{name: "unreachable", waypoints: []dijkstrapf.Point{p(0, 0), p(4, 0)}, err: dijkstrapf.ErrNoPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := dijkstrapf.LoadGrid(strings.NewReader(tourMap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			path, order, err := g.Patrol(tt.waypoints)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path.Cost != tt.cost {
+				t.Fatalf("cost %g, want %g", path.Cost, tt.cost)
+			}
+			home := tt.waypoints[0]
+			if best := bruteTour(t, tourMap, home, home, tt.waypoints[1:]); path.Cost != best {
+				t.Fatalf("cost %g, best order costs %g", path.Cost, best)
+			}
+			if len(order) != len(tt.waypoints) || order[0] != 0 {
+				t.Fatalf("order %v does not start at waypoint 0 and visit all %d", order, len(tt.waypoints))
+			}
+			var stops []dijkstrapf.Point
+			for _, i := range order {
+				stops = append(stops, tt.waypoints[i])
+			}
+			if len(stops) > 1 {
+				checkLegs(t, tourMap, path, append(stops, home))
+			}
+		})
+	}
+}
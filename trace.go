@@ -0,0 +1,79 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// WithTrace makes the solver write every step it takes to w in a
+// human-readable form, one step per line:
+//
+//	settle (2,3) dist=5
+//	  relax (2,4) 7→6
+//	  relax (3,3) ∞→6
+//	done (4,3) cost=9
+//
+// A settle line is printed when a node is taken off the frontier and its
+// distance becomes final; the relax lines below it show each neighbour
+// whose tentative distance improved, from old to new.
+func WithTrace(w io.Writer) Option {
+	return func(o *Options) { o.Trace = w }
+}
+
+// WithSettleFunc makes the solver call f with every node it settles and
+// its distance, in the order of the search, for animating it. f must not
+// mutate the graph.
+func WithSettleFunc(f func(p Point, dist float64)) Option {
+	return func(o *Options) { o.OnSettle = f }
+}
+
+// settle marks node as expanded by the running solve. Solvers that keep no
+// per-node state leave g.closed nil.
+func (g *Graph) settle(o *Options, node int, d float64) {
+	if g.closed != nil {
+		g.closed[node] = true
+	}
+	g.stats.Expanded++
+	if o.Trace != nil {
+		fmt.Fprintf(o.Trace, "settle %v dist=%s\n", g.point(node), fmtDist(d))
+	}
+	if o.Events != nil {
+		writeEvent(o.Events, "settle", g.point(node), "dist", d)
+	}
+	if o.OnSettle != nil {
+		o.OnSettle(g.point(node), d)
+	}
+}
+
+// relax reports that the tentative distance of node improved from old to d.
+func (g *Graph) relax(o *Options, node int, old, d float64) {
+	if o.Trace != nil {
+		fmt.Fprintf(o.Trace, "  relax %v %s→%s\n", g.point(node), fmtDist(old), fmtDist(d))
+	}
+	if o.Events != nil {
+		writeEvent(o.Events, "relax", g.point(node), "old", old, "new", d)
+	}
+}
+
+// finish reports the outcome of the solve.
+func (g *Graph) finish(o *Options, dst int, d float64) {
+	if o.Events != nil {
+		writeEvent(o.Events, "done", g.point(dst), "cost", d)
+	}
+	if o.Trace == nil {
+		return
+	}
+	if math.IsInf(d, 1) {
+		fmt.Fprintf(o.Trace, "done %v unreachable\n", g.point(dst))
+		return
+	}
+	fmt.Fprintf(o.Trace, "done %v cost=%s\n", g.point(dst), fmtDist(d))
+}
+
+func fmtDist(d float64) string {
+	if math.IsInf(d, 1) {
+		return "∞"
+	}
+	return fmt.Sprintf("%g", d)
+}
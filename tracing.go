@@ -0,0 +1,111 @@
+package dijkstrapf
+
+import "context"
+
+// Tracer starts the spans WithTracer records, so that a server embedding
+// the package can see the latency of path finding in its distributed
+// traces. It is small enough to wrap an OpenTelemetry tracer in a few
+// lines without the package depending on OpenTelemetry:
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, dijkstrapf.Span) {
+//		ctx, s := t.t.Start(ctx, name)
+//		return ctx, otelSpan{s}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttribute(key string, value any) {
+//		switch v := value.(type) {
+//		case int:
+//			s.SetAttributes(attribute.Int(key, v))
+//		case float64:
+//			s.SetAttributes(attribute.Float64(key, v))
+//		case bool:
+//			s.SetAttributes(attribute.Bool(key, v))
+//		case string:
+//			s.SetAttributes(attribute.String(key, v))
+//		}
+//	}
+type Tracer interface {
+	// Start begins a span named name as a child of any span in ctx and
+	// returns a context holding the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is one timed phase of a solve.
+type Span interface {
+	// SetAttribute records a value of type int, float64, bool or string.
+	SetAttribute(key string, value any)
+	// End marks the end of the phase.
+	End()
+}
+
+// WithTracer makes the solve record its phases as spans of t, under any
+// span in the context given by WithContext:
+//
+//	dijkstrapf.solve          the whole solve
+//	  dijkstrapf.preprocess   checking memory and building the adjacency
+//	  dijkstrapf.search       the main search loop
+//	    dijkstrapf.reconstruct  following the search tree back to the start
+//
+// The solve span carries the attributes dijkstrapf.algorithm,
+// dijkstrapf.grid.width, dijkstrapf.grid.height, dijkstrapf.expanded and,
+// when a path was found, dijkstrapf.path.cost and dijkstrapf.path.length;
+// otherwise dijkstrapf.error. SolveMany records one search span for each
+// start it searches from, under a dijkstrapf.solve_many span.
+func WithTracer(t Tracer) Option {
+	return func(o *Options) { o.Tracer = t }
+}
+
+// spanParent starts spans under the span held by ctx. The zero value
+// starts none.
+type spanParent struct {
+	t   Tracer
+	ctx context.Context
+}
+
+// rootSpan returns the parent of the outermost span of a solve with o.
+func rootSpan(o *Options) spanParent {
+	if o.Tracer == nil {
+		return spanParent{}
+	}
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return spanParent{o.Tracer, ctx}
+}
+
+// start begins the named span and returns it with the parent of its
+// children. Without a tracer the span does nothing.
+func (p spanParent) start(name string) (spanParent, Span) {
+	if p.t == nil {
+		return p, noSpan{}
+	}
+	ctx, s := p.t.Start(p.ctx, name)
+	return spanParent{p.t, ctx}, s
+}
+
+type noSpan struct{}
+
+func (noSpan) SetAttribute(string, any) {}
+func (noSpan) End()                     {}
+
+// setGridAttributes records the size of g on s.
+func (g *Graph) setGridAttributes(s Span) {
+	s.SetAttribute("dijkstrapf.grid.width", g.width)
+	s.SetAttribute("dijkstrapf.grid.height", g.height)
+}
+
+// setOutcomeAttributes records the work and result of a solve on s.
+func (g *Graph) setOutcomeAttributes(s Span, path Path, err error) {
+	s.SetAttribute("dijkstrapf.expanded", g.stats.Expanded)
+	if err != nil {
+		s.SetAttribute("dijkstrapf.error", err.Error())
+		return
+	}
+	s.SetAttribute("dijkstrapf.path.cost", path.Cost)
+	s.SetAttribute("dijkstrapf.path.length", len(path.Points))
+}
@@ -0,0 +1,173 @@
+package dijkstrapf
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Traffic estimates how much of the traffic over a grid passes through each
+// cell, its betweenness centrality, for predicting which corridors will be
+// busiest. It is computed once by Graph.Traffic and does not follow later
+// edits of the graph.
+type Traffic struct {
+	width, height int
+	walkable      []bool
+	load          []float64
+}
+
+// Traffic samples shortest paths to estimate the betweenness of every
+// cell. It picks samples walkable cells at random, seeded with seed, and
+// from each follows the shortest paths to every cell it reaches, pricing
+// steps with opts; a cell's load is the share of those paths that pass
+// through it, with ties between equally short paths split evenly. More
+// samples give a closer estimate; samples of 0, or more than there are
+// walkable cells, use every cell and give the exact betweenness. Paths do
+// not count towards their own ends.
+func (g *Graph) Traffic(samples int, seed int64, opts ...Option) (*Traffic, error) {
+	o := buildOptions(opts)
+	adj := g.adjacency()
+	n := g.nodeCount()
+	t := &Traffic{width: g.width, height: g.height, walkable: make([]bool, n), load: make([]float64, n)}
+	var cells []int
+	for id := range t.walkable {
+		p := g.point(id)
+		if !g.IsWall(p) && !math.IsInf(g.weights[p.Y][p.X], 1) {
+			t.walkable[id] = true
+			cells = append(cells, id)
+		}
+	}
+	if samples > 0 && samples < len(cells) {
+		r := rand.New(rand.NewSource(seed))
+		r.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+		cells = cells[:samples]
+	}
+
+	dist := make([]float64, n)
+	sigma := make([]float64, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	for _, src := range cells {
+		if o.Context != nil {
+			if err := o.Context.Err(); err != nil {
+				return nil, err
+			}
+		}
+		order, err := g.countPaths(o, adj, src, dist, sigma, preds)
+		if err != nil {
+			return nil, err
+		}
+		// Brandes' accumulation, from the farthest cell inwards.
+		clear(delta)
+		for i := len(order) - 1; i >= 0; i-- {
+			w := order[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != src {
+				t.load[w] += delta[w]
+			}
+		}
+	}
+	total := 0.0
+	for _, l := range t.load {
+		total += l
+	}
+	if total > 0 {
+		for id := range t.load {
+			t.load[id] /= total
+		}
+	}
+	return t, nil
+}
+
+// countPaths runs Dijkstra's algorithm from src, filling dist, the number
+// of shortest paths to each cell in sigma and their last steps in preds,
+// and returns the cells reached in the order they were settled.
+func (g *Graph) countPaths(o *Options, adj [][]edge, src int, dist, sigma []float64, preds [][]int) ([]int, error) {
+	for i := range dist {
+		dist[i], sigma[i], preds[i] = math.Inf(1), 0, preds[i][:0]
+	}
+	dist[src], sigma[src] = 0, 1
+	pq := NewBinaryHeap(len(dist))
+	pq.Push(src, 0)
+	var order []int
+	for pq.Len() > 0 {
+		cur, d := pq.PopMin()
+		order = append(order, cur)
+		for _, e := range adj[cur] {
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return nil, err
+			}
+			nd := d + c
+			switch {
+			case math.IsInf(nd, 1) || nd > dist[e.to]:
+			case nd == dist[e.to]:
+				sigma[e.to] += sigma[cur]
+				preds[e.to] = append(preds[e.to], cur)
+			default:
+				if math.IsInf(dist[e.to], 1) {
+					pq.Push(e.to, nd)
+				} else {
+					pq.DecreaseKey(e.to, nd)
+				}
+				dist[e.to] = nd
+				sigma[e.to] = sigma[cur]
+				preds[e.to] = append(preds[e.to][:0], cur)
+			}
+		}
+	}
+	return order, nil
+}
+
+// Load returns the share of the sampled traffic that passes through p.
+// The loads of all cells add up to 1 unless no path passes through any
+// cell.
+func (t *Traffic) Load(p Point) float64 {
+	if p.X < 0 || p.Y < 0 || p.X >= t.width || p.Y >= t.height {
+		return 0
+	}
+	return t.load[p.Y*t.width+p.X]
+}
+
+// Busiest returns the k cells of highest load, busiest first.
+func (t *Traffic) Busiest(k int) []Point {
+	var ids []int
+	for id, l := range t.load {
+		if l > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return t.load[ids[i]] > t.load[ids[j]] })
+	ids = ids[:min(max(k, 0), len(ids))]
+	out := make([]Point, len(ids))
+	for i, id := range ids {
+		out[i] = Point{id % t.width, id / t.width}
+	}
+	return out
+}
+
+// WriteHeatmap draws the load of every cell in the given style, scaled to
+// the busiest cell. Walls are drawn as '#'.
+func (t *Traffic) WriteHeatmap(w io.Writer, style HeatmapStyle) error {
+	far := 0.0
+	for _, l := range t.load {
+		far = max(far, l)
+	}
+	bw := bufio.NewWriter(w)
+	for y := 0; y < t.height; y++ {
+		for x := 0; x < t.width; x++ {
+			id := y*t.width + x
+			if !t.walkable[id] {
+				bw.WriteByte(SymbolWall)
+				continue
+			}
+			writeHeat(bw, style, t.load[id], far)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
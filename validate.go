@@ -0,0 +1,224 @@
+package dijkstrapf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidPath is wrapped by every error ValidatePath returns.
+var ErrInvalidPath = errors.New("dijkstrapf: invalid path")
+
+// ValidatePath checks that p starts at the start cell, ends at the goal,
+// moves only between neighbouring walkable cells and that p.Cost is the
+// cost of walking it. Pass the options of the solve that produced p if it
+// was priced with a custom cost function.
+func (g *Graph) ValidatePath(p Path, opts ...Option) error {
+	o := buildOptions(opts)
+	if _, _, err := g.endpoints(); err != nil {
+		return err
+	}
+	if len(p.Points) == 0 {
+		return fmt.Errorf("%w: no points", ErrInvalidPath)
+	}
+	if first := p.Points[0]; first != g.start {
+		return fmt.Errorf("%w: starts at %v, not at the start %v", ErrInvalidPath, first, g.start)
+	}
+	if last := p.Points[len(p.Points)-1]; last != g.goal {
+		return fmt.Errorf("%w: ends at %v, not at the goal %v", ErrInvalidPath, last, g.goal)
+	}
+
+	adj := g.adjacency()
+	cost := 0.0
+	for i, pt := range p.Points {
+		if !g.InBounds(pt) || g.IsWall(pt) {
+			return fmt.Errorf("%w: step %d at %v is not walkable", ErrInvalidPath, i, pt)
+		}
+		if i == 0 {
+			continue
+		}
+		from, to := g.id(p.Points[i-1]), g.id(pt)
+		e, ok := findEdge(adj[from], to)
+		if !ok {
+			return fmt.Errorf("%w: step %d from %v to %v is not a move", ErrInvalidPath, i, p.Points[i-1], pt)
+		}
+		c, err := g.stepCost(o, from, e)
+		if err != nil {
+			return err
+		}
+		cost += c
+	}
+	if math.IsInf(cost, 1) {
+		return fmt.Errorf("%w: path uses a blocked step", ErrInvalidPath)
+	}
+	if math.Abs(cost-p.Cost) > 1e-9*math.Max(1, math.Abs(cost)) {
+		return fmt.Errorf("%w: claimed cost %g, actual cost %g", ErrInvalidPath, p.Cost, cost)
+	}
+	return nil
+}
+
+// findEdge returns the cheapest of the edges to to, which AddEdge may have
+// doubled, as the solvers would take it.
+func findEdge(edges []edge, to int) (edge, bool) {
+	best, ok := edge{}, false
+	for _, e := range edges {
+		if e.to == to && (!ok || e.cost < best.cost) {
+			best, ok = e, true
+		}
+	}
+	return best, ok
+}
+
+// IssueKind classifies the problems Validate reports.
+type IssueKind int
+
+const (
+	// IssueNoStart means no start cell is set.
+	IssueNoStart IssueKind = iota
+	// IssueNoGoal means no goal cell is set.
+	IssueNoGoal
+	// IssueMarker means the start or goal markers in the grid disagree
+	// with the recorded start and goal.
+	IssueMarker
+	// IssueWeight means a cell weight is not a positive number.
+	IssueWeight
+	// IssueSealed means the goal has no walkable neighbour leading into it.
+	IssueSealed
+	// IssueUnreachable means the goal cannot be reached from the start.
+	IssueUnreachable
+	// IssueStaleAdjacency means the cached adjacency list no longer
+	// matches the grid, which points to a bug in code that edits it.
+	IssueStaleAdjacency
+)
+
+var issueNames = map[IssueKind]string{
+	IssueNoStart:        "no start",
+	IssueNoGoal:         "no goal",
+	IssueMarker:         "marker",
+	IssueWeight:         "weight",
+	IssueSealed:         "sealed goal",
+	IssueUnreachable:    "unreachable goal",
+	IssueStaleAdjacency: "stale adjacency",
+}
+
+func (k IssueKind) String() string { return issueNames[k] }
+
+// Issue is one problem found by Validate.
+type Issue struct {
+	Kind IssueKind
+	// At is the cell concerned, or (-1,-1) for problems of the whole grid.
+	At     Point
+	Detail string
+}
+
+func (i Issue) String() string {
+	if i.At.X < 0 {
+		return fmt.Sprintf("%v: %s", i.Kind, i.Detail)
+	}
+	return fmt.Sprintf("%v at %v: %s", i.Kind, i.At, i.Detail)
+}
+
+// Validate checks the grid for mistakes that would make a solve fail or
+// behave unexpectedly and returns them in a stable order, or nil if it
+// found none. It does not modify the grid, except for building the
+// adjacency list if none is cached.
+func (g *Graph) Validate() []Issue {
+	var issues []Issue
+	report := func(k IssueKind, at Point, format string, args ...any) {
+		issues = append(issues, Issue{k, at, fmt.Sprintf(format, args...)})
+	}
+	none := Point{-1, -1}
+	if !g.hasStart {
+		report(IssueNoStart, none, "set a start cell before solving")
+	}
+	if !g.hasGoal {
+		report(IssueNoGoal, none, "set a goal cell before solving")
+	}
+
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			switch c := g.gridMatrix[y][x]; {
+			case c == Start && (!g.hasStart || g.start != p):
+				report(IssueMarker, p, "start marker where no start is recorded")
+			case c == Goal && (!g.hasGoal || g.goal != p):
+				report(IssueMarker, p, "goal marker where no goal is recorded")
+			case c < Empty || c > Goal:
+				report(IssueMarker, p, "unknown cell marker %d", c)
+			}
+			if w := g.weights[y][x]; !(w > 0) {
+				report(IssueWeight, p, "weight %g", w)
+			}
+		}
+	}
+	if g.hasStart && g.Cell(g.start) != Start {
+		report(IssueMarker, g.start, "recorded start has no start marker")
+	}
+	if g.hasGoal && g.Cell(g.goal) != Goal {
+		report(IssueMarker, g.goal, "recorded goal has no goal marker")
+	}
+
+	if !g.stale && g.adjList != nil && g.pruned == nil {
+		cached := g.adjList
+		g.buildAdjacency()
+		if !sameAdjacency(cached, g.adjList) {
+			report(IssueStaleAdjacency, none, "the grid was changed without marking it stale")
+		}
+	}
+
+	if g.hasStart && g.hasGoal {
+		adj := g.adjacency()
+		src, dst := g.id(g.start), g.id(g.goal)
+		sealed := true
+		for _, edges := range adj {
+			if _, ok := findEdge(edges, dst); ok {
+				sealed = false
+				break
+			}
+		}
+		if sealed {
+			report(IssueSealed, g.goal, "no walkable cell leads into the goal")
+		} else if !g.reaches(adj, src, dst) {
+			report(IssueUnreachable, g.goal, "no path from the start %v", g.start)
+		}
+	}
+	return issues
+}
+
+// reaches reports whether dst can be reached from src.
+func (g *Graph) reaches(adj [][]edge, src, dst int) bool {
+	seen := make([]bool, len(adj))
+	seen[src] = true
+	stack := []int{src}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == dst {
+			return true
+		}
+		for _, e := range adj[cur] {
+			if !seen[e.to] {
+				seen[e.to] = true
+				stack = append(stack, e.to)
+			}
+		}
+	}
+	return false
+}
+
+func sameAdjacency(a, b [][]edge) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package dijkstrapf_test
+
+import (
+	"strings"
+	"testing"
+
+	dijkstrapf "github.com/oskjuanja/Dijkstra-Path-Finder"
+)
+
+func TestValidatePathParallelEdges(t *testing.T) {
+	g, err := dijkstrapf.LoadGrid(strings.NewReader("S#.\n.#G\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := dijkstrapf.Point{X: 0, Y: 1}, dijkstrapf.Point{X: 2, Y: 1}
+	for _, cost := range []float64{9, 2, 5} {
+		if err := g.AddEdge(a, b, cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := g.FindPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Cost != 3 {
+		t.Fatalf("cost %g, want 3", p.Cost)
+	}
+	if err := g.ValidatePath(p); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,53 @@
+package dijkstrapf
+
+import (
+	"io"
+	"os"
+)
+
+// Viewport is a rectangular window onto the grid: Width by Height cells
+// with the top-left corner at (X, Y).
+type Viewport struct {
+	X, Y, Width, Height int
+}
+
+// Contains reports whether p lies inside v.
+func (v Viewport) Contains(p Point) bool {
+	return p.X >= v.X && p.X < v.X+v.Width && p.Y >= v.Y && p.Y < v.Y+v.Height
+}
+
+// ViewportAt returns the width by height window centred on (cx, cy). The
+// window is shifted, and shrunk if the grid is smaller, so that it lies
+// inside the grid.
+func (g *Graph) ViewportAt(cx, cy, width, height int) Viewport {
+	return g.clampViewport(Viewport{cx - width/2, cy - height/2, width, height})
+}
+
+// PanViewport moves v by dx columns and dy rows, stopping at the grid
+// edges.
+func (g *Graph) PanViewport(v Viewport, dx, dy int) Viewport {
+	v.X += dx
+	v.Y += dy
+	return g.clampViewport(v)
+}
+
+func (g *Graph) clampViewport(v Viewport) Viewport {
+	v.Width = max(0, min(v.Width, g.width))
+	v.Height = max(0, min(v.Height, g.height))
+	v.X = max(0, min(v.X, g.width-v.Width))
+	v.Y = max(0, min(v.Y, g.height-v.Height))
+	return v
+}
+
+// WriteViewport draws the part of the grid inside v with the map-file
+// symbols used by WriteGrid, or '?' for weights that have none.
+func (g *Graph) WriteViewport(w io.Writer, v Viewport) error {
+	return g.Render(w, RenderOptions{Viewport: &v})
+}
+
+// PrintViewport writes the width by height window centred on (cx, cy) to
+// standard output. Unlike PrintGrid it draws map symbols, so large grids
+// can be inspected a screenful at a time.
+func (g *Graph) PrintViewport(cx, cy, width, height int) {
+	g.WriteViewport(os.Stdout, g.ViewportAt(cx, cy, width, height))
+}
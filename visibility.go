@@ -0,0 +1,114 @@
+package dijkstrapf
+
+import (
+	"fmt"
+	"math"
+)
+
+// FindPathVisibility finds the shortest any-angle path from the start to
+// the goal: the path may run in straight lines between any two cells that
+// have a line of sight to each other, not only between neighbours. Such a
+// path only turns at the cells next to wall corners, so FindPathVisibility
+// builds the visibility graph of those cells, the start and the goal, and
+// runs A* over it.
+//
+// The returned path lists only the start, the corner cells it turns at and
+// the goal; its cost is the Euclidean length of the segments between their
+// centres times the cell weight. Diagonal settings play no part. It needs
+// every walkable cell to have the same weight, and no wrapping, added or
+// removed edges, cost function or clearance; otherwise it returns
+// ErrUnsupported. Lines of sight are those of LineOfSight.
+func (g *Graph) FindPathVisibility(opts ...Option) (Path, error) {
+	return g.run("visibility", visibility, opts)
+}
+
+func visibility(g *Graph, o *Options) (Path, error) {
+	if o.Reverse {
+		return Path{}, errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, err
+	}
+	w, ok := g.uniformWeight()
+	switch {
+	case !ok:
+		return Path{}, fmt.Errorf("%w: visibility graphs need uniform cell weights", ErrUnsupported)
+	case g.wrap, len(g.extra) > 0, len(g.removed) > 0:
+		return Path{}, fmt.Errorf("%w: visibility graphs need a plain grid", ErrUnsupported)
+	case o.Cost != nil, o.Clearance > 0, o.RiskPenalty > 0:
+		return Path{}, fmt.Errorf("%w: visibility graphs cannot price steps", ErrUnsupported)
+	}
+
+	vertices := append(g.cornerCells(), src, dst)
+	length := func(a, b int) float64 {
+		p, q := g.point(a), g.point(b)
+		return w * math.Hypot(float64(p.X-q.X), float64(p.Y-q.Y))
+	}
+
+	n := g.nodeCount()
+	dist, prev := g.resetSearch()
+	queued := make([]bool, n)
+	dist[src] = 0
+	pq := o.newQueue(n)
+	pq.Push(src, length(src, dst))
+	queued[src] = true
+	for pq.Len() > 0 {
+		cur, _ := pq.PopMin()
+		queued[cur] = false
+		g.settle(o, cur, dist[cur])
+		if cur == dst {
+			break
+		}
+		if err := g.cancelled(o); err != nil {
+			return g.partial(o, dist, prev, err)
+		}
+		for _, v := range vertices {
+			if g.closed[v] || v == cur {
+				continue
+			}
+			nd := dist[cur] + length(cur, v)
+			if nd >= dist[v] || !g.LineOfSight(g.point(cur), g.point(v)) {
+				continue
+			}
+			g.relax(o, v, dist[v], nd)
+			dist[v] = nd
+			prev[v] = cur
+			f := nd + length(v, dst)
+			if queued[v] {
+				pq.DecreaseKey(v, f)
+			} else {
+				pq.Push(v, f)
+				queued[v] = true
+			}
+		}
+	}
+
+	g.finish(o, dst, dist[dst])
+	if math.IsInf(dist[dst], 1) {
+		return g.partial(o, dist, prev, ErrNoPath)
+	}
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, nil
+}
+
+// cornerCells returns the walkable cells that touch a wall only at a
+// corner: a diagonal neighbour is a wall while both cells beside it on the
+// way there are walkable. Shortest any-angle paths turn only at these.
+func (g *Graph) cornerCells() []int {
+	var out []int
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			if g.IsWall(p) {
+				continue
+			}
+			for _, d := range diagonals {
+				if g.IsWall(Point{x + d.X, y + d.Y}) && !g.IsWall(Point{x + d.X, y}) && !g.IsWall(Point{x, y + d.Y}) {
+					out = append(out, g.id(p))
+					break
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package dijkstrapf
+
+import "math"
+
+// FindPathWidest finds the path from the start to the goal whose costliest
+// single step is as cheap as possible, the bottleneck or widest path
+// problem, and returns it with the cost of that step. Among the paths with
+// the least bottleneck it returns the cheapest, so the path's Cost is the
+// usual sum of its steps.
+//
+// To route by capacity, such as an agent that needs a wide enough corridor
+// or a link that needs enough bandwidth, price each step with WithCost as
+// one over its capacity: the path that minimises the largest such cost is
+// the one that maximises the smallest capacity.
+func (g *Graph) FindPathWidest(opts ...Option) (Path, float64, error) {
+	var bottleneck float64
+	path, err := g.run("widest", func(g *Graph, o *Options) (Path, error) {
+		path, b, err := g.widest(o)
+		bottleneck = b
+		return path, err
+	}, opts)
+	return path, bottleneck, err
+}
+
+func widest(g *Graph, o *Options) (Path, error) {
+	path, _, err := g.widest(o)
+	return path, err
+}
+
+// widest finds the least bottleneck with one search that combines step
+// costs by taking their maximum, then the cheapest path under it with a
+// second, ordinary search that leaves out every costlier step.
+func (g *Graph) widest(o *Options) (Path, float64, error) {
+	if o.Reverse {
+		return Path{}, math.Inf(1), errForwardOnly
+	}
+	src, dst, err := g.endpoints()
+	if err != nil {
+		return Path{}, math.Inf(1), err
+	}
+	dist, _, err := g.bottleneckSearch(o, src, dst, math.Inf(1), math.Max)
+	if err != nil {
+		return Path{}, math.Inf(1), err
+	}
+	limit := dist[dst]
+	if math.IsInf(limit, 1) {
+		g.finish(o, dst, limit)
+		return Path{}, limit, ErrNoPath
+	}
+	expanded := g.stats.Expanded
+	add := func(d, c float64) float64 { return d + c }
+	dist, prev, err := g.bottleneckSearch(o, src, dst, limit, add)
+	g.stats.Expanded += expanded
+	if err != nil {
+		return Path{}, limit, err
+	}
+	g.finish(o, dst, dist[dst])
+	return Path{Points: g.reconstruct(prev, dst), Cost: dist[dst]}, limit, nil
+}
+
+// bottleneckSearch runs Dijkstra's algorithm from src until dst is settled,
+// extending the distance of a cell by a step with join and skipping steps
+// that cost more than limit.
+func (g *Graph) bottleneckSearch(o *Options, src, dst int, limit float64, join func(d, c float64) float64) ([]float64, []int, error) {
+	adj := g.adjacency()
+	n := g.nodeCount()
+	dist, prev := g.resetSearch()
+	dist[src] = 0
+	pq := o.newQueue(n)
+	pq.Push(src, 0)
+	for pq.Len() > 0 {
+		cur, d := pq.PopMin()
+		g.settle(o, cur, d)
+		if cur == dst {
+			break
+		}
+		if err := g.cancelled(o); err != nil {
+			return nil, nil, err
+		}
+		for _, e := range adj[cur] {
+			if g.closed[e.to] {
+				continue
+			}
+			c, err := g.stepCost(o, cur, e)
+			if err != nil {
+				return nil, nil, err
+			}
+			if c > limit || math.IsInf(c, 1) {
+				continue
+			}
+			nd := join(d, c)
+			if nd >= dist[e.to] {
+				continue
+			}
+			g.relax(o, e.to, dist[e.to], nd)
+			fresh := math.IsInf(dist[e.to], 1)
+			dist[e.to] = nd
+			prev[e.to] = cur
+			if fresh {
+				pq.Push(e.to, nd)
+			} else {
+				pq.DecreaseKey(e.to, nd)
+			}
+		}
+	}
+	return dist, prev, nil
+}
+
+func init() {
+	Register("widest", widest)
+}